@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/brendan.keane/qurl/internal/cli"
 	"github.com/brendan.keane/qurl/internal/config"
 	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/internal/filter"
 	internalhttp "github.com/brendan.keane/qurl/internal/http"
 	"github.com/brendan.keane/qurl/pkg/openapi"
 	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
@@ -37,7 +41,7 @@ func main() {
 	if err := execute(); err != nil {
 		// Use presentation layer for user-friendly error output
 		errors.PresentError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -56,11 +60,8 @@ func execute() error {
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			// Only complete paths when we have 0 args (first positional argument)
 			if len(args) == 0 {
-				// Get OpenAPI URL - try multiple sources
-				openAPIURL := os.Getenv("QURL_OPENAPI")
-				if flagVal, _ := cmd.Flags().GetString("openapi"); flagVal != "" {
-					openAPIURL = flagVal
-				}
+				// Get OpenAPI URL from flags, environment or the selected profile
+				openAPIURL := internalhttp.SpecURL(completionConfig(cmd))
 
 				// If no OpenAPI spec available, provide no completions (let shell handle files if needed)
 				if openAPIURL == "" {
@@ -124,15 +125,24 @@ func execute() error {
 			// Check if MCP mode is enabled
 			if mcpMode {
 				// Validate that incompatible flags aren't set
-				if cfg.Data != "" {
-					return errors.New(errors.ErrorTypeValidation, "cannot use --data flag with --mcp mode")
+				if cfg.Data != "" || cfg.DataRaw != "" || cfg.DataBinary != "" {
+					return errors.New(errors.ErrorTypeValidation, "cannot use --data, --data-raw or --data-binary flags with --mcp mode")
+				}
+				if len(cfg.Form) > 0 {
+					return errors.New(errors.ErrorTypeValidation, "cannot use --form flag with --mcp mode")
 				}
 				if cfg.ShowDocs {
 					return errors.New(errors.ErrorTypeValidation, "cannot use --docs flag with --mcp mode")
 				}
+				if len(cfg.PathParams) > 0 {
+					return errors.New(errors.ErrorTypeValidation, "cannot use --path-param flag with --mcp mode")
+				}
 				if cfg.IncludeHeaders {
 					return errors.New(errors.ErrorTypeValidation, "cannot use --include flag with --mcp mode")
 				}
+				if cfg.CheckResponse {
+					return errors.New(errors.ErrorTypeValidation, "cannot use --check-response flag with --mcp mode")
+				}
 
 				// Start MCP server
 				handler := cli.NewMCPHandler(*logger)
@@ -153,19 +163,62 @@ func execute() error {
 	flags.StringVar(&cfg.MCP.Description, "mcp-desc", "", "MCP server description for LLM context (env: QURL_MCP_DESCRIPTION)")
 
 	// OpenAPI and server configuration
+	flags.StringVar(&cfg.Profile, "profile", "", "Profile from the qurl config file (env: QURL_PROFILE)")
 	flags.StringVar(&cfg.OpenAPIURL, "openapi", "", "OpenAPI spec URL (env: QURL_OPENAPI)")
-	flags.StringVar(&cfg.Server, "server", "", "Server URL or index from spec (env: QURL_SERVER)")
+	flags.StringVar(&cfg.Server, "server", "", "Server URL or host, or index or description from spec (env: QURL_SERVER)")
+	flags.StringSliceVar(&cfg.ServerVars, "server-var", nil, "Server URL variables from spec (format: 'name=value')")
 
 	// HTTP configuration
 	flags.StringSliceVarP(&cfg.Methods, "request", "X", []string{"GET"}, "HTTP method to use (can be used multiple times)")
 	flags.StringSliceVarP(&cfg.Headers, "header", "H", nil, "Custom headers (format: 'Name: Value')")
 	flags.StringSliceVarP(&cfg.QueryParams, "query", "q", nil, "Query parameters (format: 'key=value')")
-	flags.StringVarP(&cfg.Data, "data", "d", "", "Request body data")
+	flags.StringSliceVarP(&cfg.PathParams, "path-param", "p", nil, "Path parameters for templated paths (format: 'name=value')")
+	flags.StringVarP(&cfg.Data, "data", "d", "", "Request body data (@file reads a file, @- reads stdin)")
+	flags.StringVar(&cfg.DataRaw, "data-raw", "", "Request body data, sent as-is without @ handling")
+	flags.StringVar(&cfg.DataBinary, "data-binary", "", "Request body data, with @file contents sent byte for byte")
+	flags.StringArrayVarP(&cfg.Form, "form", "F", nil, "Multipart form field (format: 'name=value', 'name=@file;type=mime')")
+	flags.BoolVar(&cfg.ValidateRequest, "validate", false, "Validate the request against the OpenAPI spec before sending")
+	flags.IntVar(&cfg.Retry.Retries, "retry", 0, "Retry transient failures up to N times with exponential backoff")
+	flags.DurationVar(&cfg.Retry.MaxTime, "retry-max-time", 0, "Stop retrying after this much time (e.g. 30s; default no limit)")
+	flags.StringSliceVar(&cfg.Retry.On, "retry-on", nil, "Status codes to retry (default 408,429,500,502,503,504)")
+	flags.BoolVar(&cfg.Retry.AllMethods, "retry-all-methods", false, "Also retry non-idempotent methods such as POST")
+	flags.DurationVar(&cfg.Timeouts.MaxTime, "max-time", 0, "Maximum time for the whole operation, including retries (e.g. 2m; default no limit)")
+	flags.DurationVar(&cfg.Timeouts.Connect, "connect-timeout", 0, "Maximum time to establish a connection (default no limit)")
+	flags.DurationVar(&cfg.Timeouts.Spec, "spec-timeout", 30*time.Second, "Maximum time to fetch the OpenAPI spec (0 for no limit)")
 
 	// Output configuration
 	flags.BoolVarP(&cfg.Verbose, "verbose", "v", false, "Enable verbose output")
 	flags.BoolVarP(&cfg.IncludeHeaders, "include", "i", false, "Include response headers in output")
+	flags.BoolVarP(&cfg.Fail, "fail", "f", false, "Exit with code 22 when the response status is 400 or above")
+	flags.StringVarP(&cfg.Output, "output", "o", "", "Write the response body to a file, with progress on stderr")
+	flags.StringVar(&cfg.MaxFilesize, "max-filesize", "", "Refuse response bodies larger than this (e.g. 500K, 10M, 1G)")
+	flags.BoolVar(&cfg.Raw, "raw", false, "Print the body unchanged instead of pretty-printing JSON, YAML and XML on a terminal")
+	for _, name := range filter.Languages() {
+		language, _ := filter.Lookup(name)
+		flags.String(name, "", fmt.Sprintf("Print only the result of a %s expression on a JSON response (e.g. '%s')", language.DisplayName(), language.Example()))
+	}
+	flags.StringVar(&cfg.Grep, "grep", "", "Print only the parts of the response around matches of a regular expression")
+	flags.IntVar(&cfg.Context, "context", 5, "Lines of context around --grep matches")
+	flags.BoolVar(&cfg.SSE.NDJSON, "ndjson", false, "Print Server-Sent Events as one JSON object per line")
+	flags.StringVar(&cfg.SSE.LastEventID, "last-event-id", "", "Resume a Server-Sent Events stream after this event ID")
+	flags.BoolVar(&cfg.SSE.Reconnect, "sse-reconnect", false, "Reconnect when a Server-Sent Events stream drops, resuming from the last event")
 	flags.BoolVar(&cfg.ShowDocs, "docs", false, "Show OpenAPI documentation for the endpoint")
+	flags.BoolVar(&cfg.CheckResponse, "check-response", false, "Check the response against the OpenAPI spec and fail on mismatches")
+
+	// Lambda invocation
+	flags.StringVar(&cfg.LambdaFormat, "lambda-format", "", "Event format for lambda:// URLs: v2, v1 or alb (default v2)")
+	flags.BoolVar(&cfg.LambdaLogs, "lambda-logs", false, "Print the last 4KB of Lambda function logs to stderr")
+	flags.StringVar(&cfg.LambdaInvocationType, "lambda-invocation-type", "", "Lambda invocation type: RequestResponse, Event (async, returns 202) or DryRun (checks permissions, returns 204)")
+	flags.BoolVar(&cfg.LambdaStream, "lambda-stream", false, "Invoke with response streaming and print the body as it arrives")
+	flags.StringVar(&cfg.LambdaEndpoint, "lambda-endpoint", "", "Lambda API endpoint for lambda:// URLs, e.g. a local emulator (env: QURL_LAMBDA_ENDPOINT)")
+	flags.StringVar(&cfg.LambdaContext.Stage, "lambda-stage", "", "Stage in the Lambda event request context (default $default)")
+	flags.StringVar(&cfg.LambdaContext.SourceIP, "lambda-source-ip", "", "Source IP in the Lambda event request context (default 127.0.0.1)")
+	flags.StringArrayVar(&cfg.LambdaContext.StageVars, "lambda-stage-var", nil, "Stage variable for the Lambda event (format: 'name=value')")
+	flags.StringArrayVar(&cfg.LambdaContext.PathParams, "lambda-path-param", nil, "Path parameter for the Lambda event (format: 'name=value')")
+	flags.StringArrayVar(&cfg.LambdaContext.Claims, "lambda-claim", nil, "JWT authorizer claim for the Lambda event (format: 'name=value')")
+	flags.StringSliceVar(&cfg.LambdaContext.Scopes, "lambda-scope", nil, "JWT authorizer scope for the Lambda event")
+	flags.StringArrayVar(&cfg.LambdaContext.Authorizer, "lambda-authorizer", nil, "Lambda authorizer context value for the Lambda event (format: 'name=value')")
+	flags.StringVar(&cfg.LambdaContext.IAMUserARN, "lambda-iam-arn", "", "IAM caller ARN for the Lambda event's IAM authorizer")
 
 	// Authentication
 	flags.BoolVar(&cfg.SigV4Enabled, "aws-sigv4", false, "Sign requests with AWS SigV4")
@@ -180,10 +233,7 @@ func execute() error {
 		commonMethods := []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
 
 		// Try to enhance with OpenAPI-specific methods, but don't fail if we can't
-		openAPIURL := os.Getenv("QURL_OPENAPI")
-		if flagVal, _ := cmd.Flags().GetString("openapi"); flagVal != "" {
-			openAPIURL = flagVal
-		}
+		openAPIURL := internalhttp.SpecURL(completionConfig(cmd))
 
 		if openAPIURL != "" {
 			// Quick attempt to get OpenAPI-specific methods
@@ -208,13 +258,26 @@ func execute() error {
 		return commonMethods, cobra.ShellCompDirectiveNoFileComp
 	})
 
+	// Register completion function for path parameters
+	rootCmd.RegisterFlagCompletionFunc("path-param", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		// Offer name= for each {name} placeholder in the path argument
+		var completions []string
+		for _, name := range openapi.TemplateParams(args[0]) {
+			completions = append(completions, name+"=")
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	})
+
 	// Register completion function for server flag
 	rootCmd.RegisterFlagCompletionFunc("server", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		// Get OpenAPI URL from environment or flags
-		openAPIURL := os.Getenv("QURL_OPENAPI")
-		if flagVal, _ := cmd.Flags().GetString("openapi"); flagVal != "" {
-			openAPIURL = flagVal
-		}
+		// Get OpenAPI URL from flags, environment or the selected profile
+		current := completionConfig(cmd)
+		openAPIURL := internalhttp.SpecURL(current)
 
 		// If no OpenAPI spec available, no completions
 		if openAPIURL == "" {
@@ -228,57 +291,77 @@ func execute() error {
 		}
 
 		// Check if AWS SigV4 is enabled for authenticated completion
-		if sigv4, _ := cmd.Flags().GetBool("aws-sigv4"); sigv4 {
+		if current.SigV4Enabled {
 			// Build minimal config for authentication
 			tempCfg := &config.Config{
 				SigV4Enabled: true,
+				SigV4Service: current.SigV4Service,
 			}
-			if service, _ := cmd.Flags().GetString("aws-service"); service != "" {
-				tempCfg.SigV4Service = service
-			} else {
+			if tempCfg.SigV4Service == "" {
 				tempCfg.SigV4Service = "execute-api" // default
 			}
 
 			// Create authenticated client
 			authClient := internalhttp.NewAuthenticatedHTTPClient(tempCfg, log.Logger)
-			viewer := openapi.NewViewer(authClient, openAPIURL)
-
-			servers, err := viewer.GetServers()
-			if err != nil || len(servers) == 0 {
-				return nil, cobra.ShellCompDirectiveNoFileComp
-			}
-
-			// Return server URLs as completion options
-			var completions []string
-			for _, server := range servers {
-				if server.URL != "" {
-					completions = append(completions, server.URL)
-				}
-			}
-
-			return completions, cobra.ShellCompDirectiveNoFileComp
+			return serverCompletions(cmd, openapi.NewViewer(authClient, openAPIURL))
 		}
 
 		// Use regular HTTP client for non-authenticated requests
-		viewer := openapi.NewViewer(httpClient, openAPIURL)
+		return serverCompletions(cmd, openapi.NewViewer(httpClient, openAPIURL))
+	})
 
-		servers, err := viewer.GetServers()
-		if err != nil || len(servers) == 0 {
-			return nil, cobra.ShellCompDirectiveNoFileComp
-		}
+	// Register completion function for profile flag
+	rootCmd.RegisterFlagCompletionFunc("profile", profileCompletions)
 
-		// Return server URLs as completion options
-		var completions []string
-		for _, server := range servers {
-			if server.URL != "" {
-				completions = append(completions, server.URL)
-			}
-		}
+	// Register completion function for lambda-format flag
+	rootCmd.RegisterFlagCompletionFunc("lambda-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{
+			"v2\tAPI Gateway HTTP API (payload 2.0)",
+			"v1\tAPI Gateway REST API (payload 1.0)",
+			"alb\tApplication Load Balancer",
+		}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	// Register completion function for lambda-invocation-type flag
+	rootCmd.RegisterFlagCompletionFunc("lambda-invocation-type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{
+			"RequestResponse\tWait for the function's response",
+			"Event\tQueue the event and return 202",
+			"DryRun\tCheck permissions and return 204",
+		}, cobra.ShellCompDirectiveNoFileComp
+	})
 
-		return completions, cobra.ShellCompDirectiveNoFileComp
+	// Add profile command for inspecting the config file
+	profileHandler := cli.NewProfileHandler(log.Logger)
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Inspect profiles from the qurl config file",
+		// Skip the root config loading so a broken profile can still be inspected
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	profileCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List profiles (the active one is marked with *)",
+		Args:  cobra.NoArgs,
+		RunE:  profileHandler.List,
+	})
+	profileCmd.AddCommand(&cobra.Command{
+		Use:   "show [name]",
+		Short: "Show a profile (defaults to the active one)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  profileHandler.Show,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return profileCompletions(cmd, args, toComplete)
+		},
 	})
+	rootCmd.AddCommand(profileCmd)
 
-	// Add completion command (keeping this as the only subcommand for shell completions)
+	// Add completion command for generating shell completion scripts
 	rootCmd.AddCommand(&cobra.Command{
 		Use:   "completion [bash|zsh|fish|powershell]",
 		Short: "Generate completion script",
@@ -315,5 +398,72 @@ PowerShell:
 		},
 	})
 
-	return rootCmd.Execute()
-}
\ No newline at end of file
+	// Unknown or malformed flags are usage errors, like other configuration problems
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return errors.New(errors.ErrorTypeConfig, err.Error()).
+			WithContext("suggestion", "run '"+cmd.CommandPath()+" --help' for usage")
+	})
+
+	// Ctrl-C cancels the command's context so requests stop cleanly; once it
+	// has, a second Ctrl-C kills the process as usual
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// serverCompletions returns the spec's server URLs, with server variables expanded
+// using any --server-var values already on the command line
+func serverCompletions(cmd *cobra.Command, viewer *openapi.Viewer) ([]string, cobra.ShellCompDirective) {
+	if serverVars, _ := cmd.Flags().GetStringSlice("server-var"); len(serverVars) > 0 {
+		if vars, err := internalhttp.ParseServerVariables(serverVars); err == nil {
+			viewer.SetServerVariables(vars)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Second)
+	defer cancel()
+
+	servers, err := viewer.ResolvedServers(ctx)
+	if err != nil || len(servers) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Return server URLs as completion options, described by the spec's server description
+	var completions []string
+	for _, server := range servers {
+		if server.URL == "" {
+			continue
+		}
+		if server.Description != "" {
+			completions = append(completions, server.URL+"\t"+server.Description)
+		} else {
+			completions = append(completions, server.URL)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionConfig loads the configuration for shell completion, including the
+// selected profile, so completions see the same spec and auth as requests
+func completionConfig(cmd *cobra.Command) *config.Config {
+	cfg, err := config.LoadFromFlags(cmd.Flags())
+	if err != nil {
+		return config.NewConfig()
+	}
+	return cfg
+}
+
+// profileCompletions returns the profile names from the qurl config file
+func profileCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	file, err := config.LoadFile(config.FilePath())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return file.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+)
+
+func TestFlagErrorsAreConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown flag", []string{"--bogus-flag"}},
+		{"missing flag value", []string{"--server"}},
+		{"invalid flag value", []string{"--max-time", "soon"}},
+		{"unknown subcommand flag", []string{"profile", "--bogus-flag"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := os.Args
+			defer func() { os.Args = args }()
+			os.Args = append([]string{"qurl"}, tt.args...)
+
+			err := execute()
+			if !errors.IsType(err, errors.ErrorTypeConfig) {
+				t.Fatalf("expected a config error, got %v", err)
+			}
+			if code := errors.ExitCode(err); code != 2 {
+				t.Errorf("exit code = %d, want 2", code)
+			}
+		})
+	}
+}
@@ -68,8 +68,8 @@ func (h *HTTPHandler) Execute(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// The command context is cancelled on Ctrl-C; --max-time bounds the whole operation
+	ctx, cancel := withMaxTime(commandContext(cmd), cfg.Timeouts.MaxTime)
 	defer cancel()
 
 	// Handle documentation request
@@ -104,4 +104,20 @@ func (h *HTTPHandler) Execute(cmd *cobra.Command, args []string) error {
 	// Execute HTTP request
 	h.logger.Debug().Msg("executing HTTP request")
 	return executor.Execute(ctx, path)
-}
\ No newline at end of file
+}
+
+// commandContext returns the command's context, which main cancels on an interrupt
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// withMaxTime limits ctx to maxTime; zero means no limit
+func withMaxTime(ctx context.Context, maxTime time.Duration) (context.Context, context.CancelFunc) {
+	if maxTime <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, maxTime)
+}
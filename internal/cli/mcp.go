@@ -65,5 +65,5 @@ func (h *MCPHandler) Execute(cmd *cobra.Command, args []string) error {
 	h.logger.Debug().Msg("MCP server created, starting message loop")
 
 	// Start the server
-	return server.Start()
+	return server.Start(commandContext(cmd))
 }
\ No newline at end of file
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileHandler handles the profile subcommands
+type ProfileHandler struct {
+	logger zerolog.Logger
+}
+
+// NewProfileHandler creates a new profile command handler
+func NewProfileHandler(logger zerolog.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		logger: logger.With().Str("handler", "profile").Logger(),
+	}
+}
+
+// List prints the profile names from the config file, marking the active one
+func (h *ProfileHandler) List(cmd *cobra.Command, args []string) error {
+	path := config.FilePath()
+	file, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	names := file.ProfileNames()
+	if len(names) == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "No profiles defined in %s\n", path)
+		return nil
+	}
+
+	active := config.ProfileName(cmd.Flags())
+	for _, name := range names {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", marker, name)
+	}
+
+	h.logger.Debug().Str("path", path).Int("profiles", len(names)).Msg("listed profiles")
+	return nil
+}
+
+// Show prints a profile as YAML: the named one, or the active one if no name is given
+func (h *ProfileHandler) Show(cmd *cobra.Command, args []string) error {
+	name := config.ProfileName(cmd.Flags())
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" {
+		return errors.New(errors.ErrorTypeValidation, "no profile selected").
+			WithContext("suggestion", "pass a profile name, use --profile or set QURL_PROFILE")
+	}
+
+	file, err := config.LoadFile(config.FilePath())
+	if err != nil {
+		return err
+	}
+
+	profile, err := file.Profile(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent(2)
+	if err := encoder.Encode(map[string]*config.Profile{name: profile}); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeInternal, "failed to format profile")
+	}
+	return encoder.Close()
+}
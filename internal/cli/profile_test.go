@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+)
+
+func newProfileCommand(t *testing.T, profile string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "profiles:\n  dev:\n    server: http://localhost:8080\n  prod:\n    openapi: https://api.example.com/openapi.json\n    aws-sigv4: true\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("QURL_CONFIG", path)
+	t.Setenv("QURL_PROFILE", "")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("profile", profile, "")
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	return cmd, &out
+}
+
+func TestProfileHandler_List(t *testing.T) {
+	cmd, out := newProfileCommand(t, "prod")
+
+	if err := NewProfileHandler(zerolog.Nop()).List(cmd, nil); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	if out.String() != "  dev\n* prod\n" {
+		t.Errorf("Unexpected list output:\n%s", out.String())
+	}
+}
+
+func TestProfileHandler_Show(t *testing.T) {
+	cmd, out := newProfileCommand(t, "")
+
+	handler := NewProfileHandler(zerolog.Nop())
+	if err := handler.Show(cmd, []string{"prod"}); err != nil {
+		t.Fatalf("Show failed: %v", err)
+	}
+
+	for _, expected := range []string{"prod:", "openapi: https://api.example.com/openapi.json", "aws-sigv4: true"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+
+	if err := handler.Show(cmd, nil); err == nil {
+		t.Error("Expected error when no profile is selected")
+	}
+	if err := handler.Show(cmd, []string{"qa"}); err == nil {
+		t.Error("Expected error for unknown profile")
+	}
+}
@@ -3,9 +3,12 @@ package config
 import (
 	"context"
 	"os"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/internal/filter"
 	"github.com/rs/zerolog"
 	"github.com/spf13/pflag"
 )
@@ -13,25 +16,92 @@ import (
 // Config holds all application configuration
 type Config struct {
 	// Core HTTP settings
+	Profile       string // Named profile from the config file
 	OpenAPIURL    string
 	Methods       []string // Changed from Method to Methods (slice)
 	Path          string
 	Headers       []string
 	QueryParams   []string
-	Data          string
+	PathParams    []string
+	Data          string // Request body; @file reads a file, @- reads stdin
+	DataRaw       string // Request body used literally, without @ handling
+	DataBinary    string // Like Data, but file contents are sent byte for byte
+	Form          []string // Multipart fields: name=value, name=@file[;type=mime], name=<file
 	Server        string
+	ServerVars    []string // Overrides for {variable} placeholders in spec server URLs
+	LambdaFormat  string   // Event format for lambda:// URLs: v2 (default), v1 or alb
+	LambdaLogs    bool     // Print the tail of the function's logs to stderr
+	LambdaEndpoint string  // Lambda API endpoint override, e.g. a local emulator
+	LambdaInvocationType string // RequestResponse (default), Event or DryRun
+	LambdaStream  bool     // Invoke with response streaming, printing the body as it arrives
 	Verbose       bool
 	IncludeHeaders bool
+	Fail          bool // Return an error for 4xx and 5xx responses, after printing them
+	Output        string // Write the body to this file instead of stdout; "-" is stdout
+	MaxFilesize   string // Refuse response bodies larger than this, e.g. 500K, 10M, 1G
+	Raw           bool   // Print bodies unchanged instead of pretty-printing them on a terminal
+	Queries       map[string]string // Filter expressions by language name, set by the flag of the same name, e.g. --jq
+	Grep          string // Print only the parts of the body around matches of this regex
+	Context       int    // Lines of context around --grep matches
 	ShowDocs      bool
+	ValidateRequest bool // Check the request against the OpenAPI operation before sending
+	CheckResponse bool   // Check the response against the OpenAPI operation after receiving
 
 	// Authentication
 	SigV4Enabled bool
 	SigV4Service string
 
+	// Synthetic request context for lambda:// events
+	LambdaContext LambdaContextConfig
+
+	// Retries of failed requests
+	Retry RetryConfig
+
+	// Request, connection and spec fetch time limits
+	Timeouts TimeoutConfig
+
+	// Server-Sent Events output and resumption
+	SSE SSEConfig
+
 	// MCP settings
 	MCP MCPConfig
 }
 
+// RetryConfig controls retries of transient failures
+type RetryConfig struct {
+	Retries    int           // Maximum number of retries; 0 disables retrying
+	MaxTime    time.Duration // Give up retrying once this much time has passed; 0 means no limit
+	On         []string      // Status codes to retry; empty selects 408, 429, 500, 502, 503 and 504
+	AllMethods bool          // Also retry non-idempotent methods such as POST and PATCH
+}
+
+// TimeoutConfig limits how long requests may take. A zero duration means no limit.
+type TimeoutConfig struct {
+	MaxTime time.Duration // Whole operation, including retries and reading the response
+	Connect time.Duration // Establishing each TCP connection
+	Spec    time.Duration // Fetching the OpenAPI specification
+}
+
+// SSEConfig controls how text/event-stream responses are read
+type SSEConfig struct {
+	NDJSON      bool   // Print each event as a JSON object on its own line
+	LastEventID string // Resume a stream by sending Last-Event-ID
+	Reconnect   bool   // Reconnect when the stream drops, resuming from the last event
+}
+
+// LambdaContextConfig holds the request context placed in lambda:// events,
+// so authorizer- and stage-dependent routes can be invoked directly
+type LambdaContextConfig struct {
+	Stage      string   // requestContext.stage
+	SourceIP   string   // Caller source IP
+	StageVars  []string // Stage variables (format: name=value)
+	PathParams []string // Event pathParameters (format: name=value)
+	Claims     []string // JWT authorizer claims (format: name=value)
+	Scopes     []string // JWT authorizer scopes
+	Authorizer []string // Lambda authorizer context (format: name=value)
+	IAMUserARN string   // Caller ARN for an IAM-authorized route
+}
+
 // MCPConfig holds MCP-specific configuration
 type MCPConfig struct {
 	Enabled        bool
@@ -97,14 +167,70 @@ func LoadFromFlags(flags *pflag.FlagSet) (*Config, error) {
 		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get query flag")
 	}
 
+	if config.PathParams, err = flags.GetStringSlice("path-param"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get path-param flag")
+	}
+
 	if config.Data, err = flags.GetString("data"); err != nil {
 		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get data flag")
 	}
 
+	if config.DataRaw, err = flags.GetString("data-raw"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get data-raw flag")
+	}
+
+	if config.DataBinary, err = flags.GetString("data-binary"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get data-binary flag")
+	}
+
+	if config.Form, err = flags.GetStringArray("form"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get form flag")
+	}
+
 	if config.Server, err = flags.GetString("server"); err != nil {
 		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get server flag")
 	}
 
+	if config.ServerVars, err = flags.GetStringSlice("server-var"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get server-var flag")
+	}
+
+	if config.LambdaFormat, err = flags.GetString("lambda-format"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-format flag")
+	}
+
+	if config.LambdaLogs, err = flags.GetBool("lambda-logs"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-logs flag")
+	}
+
+	if config.LambdaEndpoint, err = flags.GetString("lambda-endpoint"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-endpoint flag")
+	}
+
+	if config.LambdaInvocationType, err = flags.GetString("lambda-invocation-type"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-invocation-type flag")
+	}
+
+	if config.LambdaStream, err = flags.GetBool("lambda-stream"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-stream flag")
+	}
+
+	if err := config.LambdaContext.loadFromFlags(flags); err != nil {
+		return nil, err
+	}
+
+	if err := config.Retry.loadFromFlags(flags); err != nil {
+		return nil, err
+	}
+
+	if err := config.Timeouts.loadFromFlags(flags); err != nil {
+		return nil, err
+	}
+
+	if err := config.SSE.loadFromFlags(flags); err != nil {
+		return nil, err
+	}
+
 	if config.Verbose, err = flags.GetBool("verbose"); err != nil {
 		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get verbose flag")
 	}
@@ -113,10 +239,54 @@ func LoadFromFlags(flags *pflag.FlagSet) (*Config, error) {
 		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get include flag")
 	}
 
+	if config.Fail, err = flags.GetBool("fail"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get fail flag")
+	}
+
+	if config.Output, err = flags.GetString("output"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get output flag")
+	}
+
+	if config.MaxFilesize, err = flags.GetString("max-filesize"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get max-filesize flag")
+	}
+
+	if config.Raw, err = flags.GetBool("raw"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get raw flag")
+	}
+
+	// Each registered filter language has a flag of the same name
+	config.Queries = map[string]string{}
+	for _, language := range filter.Languages() {
+		expression, err := flags.GetString(language)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get "+language+" flag")
+		}
+		if expression != "" {
+			config.Queries[language] = expression
+		}
+	}
+
+	if config.Grep, err = flags.GetString("grep"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get grep flag")
+	}
+
+	if config.Context, err = flags.GetInt("context"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get context flag")
+	}
+
 	if config.ShowDocs, err = flags.GetBool("docs"); err != nil {
 		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get docs flag")
 	}
 
+	if config.ValidateRequest, err = flags.GetBool("validate"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get validate flag")
+	}
+
+	if config.CheckResponse, err = flags.GetBool("check-response"); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get check-response flag")
+	}
+
 	// Authentication flags
 	if config.SigV4Enabled, err = flags.GetBool("aws-sigv4"); err != nil {
 		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to get aws-sigv4 flag")
@@ -143,6 +313,11 @@ func LoadFromFlags(flags *pflag.FlagSet) (*Config, error) {
 		}
 	}
 
+	// Get Lambda endpoint from environment if not set via flag
+	if config.LambdaEndpoint == "" {
+		config.LambdaEndpoint = os.Getenv("QURL_LAMBDA_ENDPOINT")
+	}
+
 	// Configure debug mode for verbose flag
 	if config.Verbose {
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
@@ -160,6 +335,15 @@ func LoadFromFlags(flags *pflag.FlagSet) (*Config, error) {
 		}
 	}
 
+	// Merge the selected profile from the config file under flags and environment
+	if config.Profile = ProfileName(flags); config.Profile != "" {
+		profile, err := LoadProfile(flags)
+		if err != nil {
+			return nil, err
+		}
+		config.applyProfile(profile, flags)
+	}
+
 	// Propagate settings to MCP config
 	config.MCP.Headers = config.Headers
 	config.MCP.SigV4 = config.SigV4Enabled
@@ -173,6 +357,106 @@ func LoadFromFlags(flags *pflag.FlagSet) (*Config, error) {
 	return config, nil
 }
 
+// loadFromFlags reads the --lambda-* request context flags
+func (c *LambdaContextConfig) loadFromFlags(flags *pflag.FlagSet) error {
+	var err error
+
+	if c.Stage, err = flags.GetString("lambda-stage"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-stage flag")
+	}
+
+	if c.SourceIP, err = flags.GetString("lambda-source-ip"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-source-ip flag")
+	}
+
+	if c.StageVars, err = flags.GetStringArray("lambda-stage-var"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-stage-var flag")
+	}
+
+	if c.PathParams, err = flags.GetStringArray("lambda-path-param"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-path-param flag")
+	}
+
+	if c.Claims, err = flags.GetStringArray("lambda-claim"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-claim flag")
+	}
+
+	if c.Scopes, err = flags.GetStringSlice("lambda-scope"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-scope flag")
+	}
+
+	if c.Authorizer, err = flags.GetStringArray("lambda-authorizer"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-authorizer flag")
+	}
+
+	if c.IAMUserARN, err = flags.GetString("lambda-iam-arn"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get lambda-iam-arn flag")
+	}
+
+	return nil
+}
+
+// loadFromFlags reads the --retry flags
+func (c *RetryConfig) loadFromFlags(flags *pflag.FlagSet) error {
+	var err error
+
+	if c.Retries, err = flags.GetInt("retry"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get retry flag")
+	}
+
+	if c.MaxTime, err = flags.GetDuration("retry-max-time"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get retry-max-time flag")
+	}
+
+	if c.On, err = flags.GetStringSlice("retry-on"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get retry-on flag")
+	}
+
+	if c.AllMethods, err = flags.GetBool("retry-all-methods"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get retry-all-methods flag")
+	}
+
+	return nil
+}
+
+// loadFromFlags reads --max-time, --connect-timeout and --spec-timeout
+func (c *TimeoutConfig) loadFromFlags(flags *pflag.FlagSet) error {
+	var err error
+
+	if c.MaxTime, err = flags.GetDuration("max-time"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get max-time flag")
+	}
+
+	if c.Connect, err = flags.GetDuration("connect-timeout"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get connect-timeout flag")
+	}
+
+	if c.Spec, err = flags.GetDuration("spec-timeout"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get spec-timeout flag")
+	}
+
+	return nil
+}
+
+// loadFromFlags reads --ndjson, --last-event-id and --sse-reconnect
+func (c *SSEConfig) loadFromFlags(flags *pflag.FlagSet) error {
+	var err error
+
+	if c.NDJSON, err = flags.GetBool("ndjson"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get ndjson flag")
+	}
+
+	if c.LastEventID, err = flags.GetString("last-event-id"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get last-event-id flag")
+	}
+
+	if c.Reconnect, err = flags.GetBool("sse-reconnect"); err != nil {
+		return errors.Wrap(err, errors.ErrorTypeConfig, "failed to get sse-reconnect flag")
+	}
+
+	return nil
+}
+
 // PrimaryMethod returns the first method for HTTP requests
 func (c *Config) PrimaryMethod() string {
 	if len(c.Methods) > 0 {
@@ -244,9 +528,74 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Every query language has a flag of its own, alongside --grep
+	var filters, filterFlags []string
+	for _, language := range filter.Languages() {
+		filterFlags = append(filterFlags, "--"+language)
+		if c.Queries[language] != "" {
+			filters = append(filters, "--"+language)
+		}
+	}
+	filterFlags = append(filterFlags, "--grep")
+	if c.Grep != "" {
+		filters = append(filters, "--grep")
+	}
+	if len(filters) > 1 {
+		return errors.New(errors.ErrorTypeValidation, "only one response filter can be used at a time").
+			WithContext("filters", filters).
+			WithContext("suggestion", "choose one of "+strings.Join(filterFlags, ", "))
+	}
+
+	// Check the filter before any request is sent
+	if language, expression := c.Query(); language != "" {
+		if err := filter.Compile(language, expression); err != nil {
+			return errors.Wrap(err, errors.ErrorTypeValidation, "invalid response filter").
+				WithContext("language", language).
+				WithContext("expression", expression)
+		}
+	}
+	if c.Grep != "" {
+		if _, err := regexp.Compile(c.Grep); err != nil {
+			return errors.Wrap(err, errors.ErrorTypeValidation, "invalid response filter").
+				WithContext("grep", c.Grep)
+		}
+	}
+
+	if c.Context < 0 {
+		return errors.New(errors.ErrorTypeValidation, "context must not be negative").
+			WithContext("context", c.Context)
+	}
+
+	timeouts := []struct {
+		flag  string
+		value time.Duration
+	}{
+		{"max-time", c.Timeouts.MaxTime},
+		{"connect-timeout", c.Timeouts.Connect},
+		{"spec-timeout", c.Timeouts.Spec},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			return errors.New(errors.ErrorTypeValidation, "timeout must not be negative").
+				WithContext("flag", timeout.flag).
+				WithContext("value", timeout.value.String())
+		}
+	}
+
 	return nil
 }
 
+// Query returns the JSON query language and expression set by --jmespath,
+// --jq, --jsonpath or another language flag, or empty strings when none is set
+func (c *Config) Query() (language, expression string) {
+	for _, language := range filter.Languages() {
+		if expression := c.Queries[language]; expression != "" {
+			return language, expression
+		}
+	}
+	return "", ""
+}
+
 // ValidateMCP ensures MCP configuration is valid
 func (c *MCPConfig) Validate() error {
 	if c.OpenAPIURL == "" {
@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/internal/filter"
 	"github.com/spf13/pflag"
 )
 
@@ -68,6 +71,108 @@ func TestConfig_Validation_EmptyMethods(t *testing.T) {
 	}
 }
 
+func TestConfig_Validation_Timeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts TimeoutConfig
+		wantFlag string
+	}{
+		{"no limits", TimeoutConfig{}, ""},
+		{"limits", TimeoutConfig{MaxTime: time.Minute, Connect: 5 * time.Second, Spec: 10 * time.Second}, ""},
+		{"negative max time", TimeoutConfig{MaxTime: -time.Second}, "max-time"},
+		{"negative connect timeout", TimeoutConfig{Connect: -time.Second}, "connect-timeout"},
+		{"negative spec timeout", TimeoutConfig{Spec: -time.Second}, "spec-timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Timeouts = tt.timeouts
+
+			err := cfg.Validate()
+			if tt.wantFlag == "" {
+				if err != nil {
+					t.Errorf("Config validation should pass: %v", err)
+				}
+				return
+			}
+			if !errors.IsType(err, errors.ErrorTypeValidation) {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+			if flag := errors.GetContext(err)["flag"]; flag != tt.wantFlag {
+				t.Errorf("flag: got %v, expected %s", flag, tt.wantFlag)
+			}
+		})
+	}
+}
+
+func TestConfig_Validation_Filters(t *testing.T) {
+	tests := []struct {
+		name    string
+		queries map[string]string
+		grep    string
+		context int
+		wantErr bool
+	}{
+		{"no filter", nil, "", 5, false},
+		{"jmespath", map[string]string{"jmespath": "[].name"}, "", 5, false},
+		{"jsonpath", map[string]string{"jsonpath": "$[*].name"}, "", 5, false},
+		{"jq", map[string]string{"jq": ".[].name"}, "", 5, false},
+		{"grep", nil, "ERROR", 2, false},
+		{"jmespath and grep", map[string]string{"jmespath": "[].name"}, "ERROR", 5, true},
+		{"jsonpath and jq", map[string]string{"jsonpath": "$[*].name", "jq": ".[].name"}, "", 5, true},
+		{"negative context", nil, "ERROR", -1, true},
+		{"invalid jmespath", map[string]string{"jmespath": "[?"}, "", 5, true},
+		{"invalid jsonpath", map[string]string{"jsonpath": "$["}, "", 5, true},
+		{"invalid jq", map[string]string{"jq": ".items["}, "", 5, true},
+		{"invalid grep", nil, "(", 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Queries = tt.queries
+			cfg.Grep = tt.grep
+			cfg.Context = tt.context
+
+			err := cfg.Validate()
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("Config validation should pass: %v", err)
+				}
+				return
+			}
+			if !errors.IsType(err, errors.ErrorTypeValidation) {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_Query(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            Config
+		wantLanguage   string
+		wantExpression string
+	}{
+		{"none", Config{Grep: "ERROR"}, "", ""},
+		{"jmespath", Config{Queries: map[string]string{"jmespath": "[].name"}}, "jmespath", "[].name"},
+		{"jsonpath", Config{Queries: map[string]string{"jsonpath": "$[*].name"}}, "jsonpath", "$[*].name"},
+		{"jq", Config{Queries: map[string]string{"jq": ".[].name"}}, "jq", ".[].name"},
+		{"empty expression", Config{Queries: map[string]string{"jq": ""}}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, expression := tt.cfg.Query()
+			if language != tt.wantLanguage || expression != tt.wantExpression {
+				t.Errorf("Query(): got (%q, %q), expected (%q, %q)", language, expression, tt.wantLanguage, tt.wantExpression)
+			}
+		})
+	}
+}
+
 func TestConfig_PrimaryMethod(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -196,6 +301,31 @@ func TestLoadFromFlags_EnvironmentVariables(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "QURL_LAMBDA_ENDPOINT environment variable",
+			envVars: map[string]string{
+				"QURL_LAMBDA_ENDPOINT": "http://localhost:3001",
+			},
+			expectedConfig: func(c *Config) {
+				if c.LambdaEndpoint != "http://localhost:3001" {
+					t.Errorf("LambdaEndpoint: got %q, expected %q", c.LambdaEndpoint, "http://localhost:3001")
+				}
+			},
+		},
+		{
+			name: "flag overrides QURL_LAMBDA_ENDPOINT",
+			envVars: map[string]string{
+				"QURL_LAMBDA_ENDPOINT": "http://localhost:3001",
+			},
+			flagValues: map[string]string{
+				"lambda-endpoint": "http://localhost:9000",
+			},
+			expectedConfig: func(c *Config) {
+				if c.LambdaEndpoint != "http://localhost:9000" {
+					t.Errorf("LambdaEndpoint: got %q, expected flag value %q", c.LambdaEndpoint, "http://localhost:9000")
+				}
+			},
+		},
 		{
 			name: "QURL_LOG_LEVEL environment variable",
 			envVars: map[string]string{
@@ -284,8 +414,10 @@ func TestLoadFromFlags_EnvironmentVariables(t *testing.T) {
 			os.Unsetenv("QURL_OPENAPI")
 			os.Unsetenv("OPENAPI_URL")
 			os.Unsetenv("QURL_SERVER")
+			os.Unsetenv("QURL_LAMBDA_ENDPOINT")
 			os.Unsetenv("QURL_LOG_LEVEL")
 			os.Unsetenv("QURL_LOG_FORMAT")
+			os.Unsetenv("QURL_PROFILE")
 
 			// Set test environment variables
 			for key, value := range tt.envVars {
@@ -297,14 +429,54 @@ func TestLoadFromFlags_EnvironmentVariables(t *testing.T) {
 			var cfg Config
 
 			// Define flags as they are in main.go
+			flags.StringVar(&cfg.Profile, "profile", "", "Config file profile")
 			flags.StringVar(&cfg.OpenAPIURL, "openapi", "", "OpenAPI specification URL")
 			flags.StringVar(&cfg.Server, "server", "", "Server URL or index")
+			flags.StringSliceVar(&cfg.ServerVars, "server-var", nil, "Server variables")
+			flags.StringVar(&cfg.LambdaFormat, "lambda-format", "", "Lambda event format")
+			flags.BoolVar(&cfg.LambdaLogs, "lambda-logs", false, "Lambda log tail")
+			flags.StringVar(&cfg.LambdaEndpoint, "lambda-endpoint", "", "Lambda API endpoint")
+			flags.StringVar(&cfg.LambdaInvocationType, "lambda-invocation-type", "", "Lambda invocation type")
+			flags.BoolVar(&cfg.LambdaStream, "lambda-stream", false, "Lambda response streaming")
+			flags.StringVar(&cfg.LambdaContext.Stage, "lambda-stage", "", "Lambda event stage")
+			flags.StringVar(&cfg.LambdaContext.SourceIP, "lambda-source-ip", "", "Lambda event source IP")
+			flags.StringArrayVar(&cfg.LambdaContext.StageVars, "lambda-stage-var", nil, "Lambda stage variables")
+			flags.StringArrayVar(&cfg.LambdaContext.PathParams, "lambda-path-param", nil, "Lambda path parameters")
+			flags.StringArrayVar(&cfg.LambdaContext.Claims, "lambda-claim", nil, "Lambda JWT claims")
+			flags.StringSliceVar(&cfg.LambdaContext.Scopes, "lambda-scope", nil, "Lambda JWT scopes")
+			flags.StringArrayVar(&cfg.LambdaContext.Authorizer, "lambda-authorizer", nil, "Lambda authorizer context")
+			flags.StringVar(&cfg.LambdaContext.IAMUserARN, "lambda-iam-arn", "", "Lambda IAM caller")
 			flags.StringSliceVar(&cfg.Methods, "request", []string{"GET"}, "HTTP method")
 			flags.StringSliceVar(&cfg.Headers, "header", nil, "Custom headers")
 			flags.StringSliceVar(&cfg.QueryParams, "query", nil, "Query parameters")
+			flags.StringSliceVar(&cfg.PathParams, "path-param", nil, "Path parameters")
 			flags.StringVar(&cfg.Data, "data", "", "Request body data")
+			flags.StringVar(&cfg.DataRaw, "data-raw", "", "Literal request body data")
+			flags.StringVar(&cfg.DataBinary, "data-binary", "", "Binary request body data")
+			flags.StringArrayVar(&cfg.Form, "form", nil, "Multipart form fields")
+			flags.BoolVar(&cfg.ValidateRequest, "validate", false, "Validate request")
+			flags.IntVar(&cfg.Retry.Retries, "retry", 0, "Retries")
+			flags.DurationVar(&cfg.Retry.MaxTime, "retry-max-time", 0, "Retry time limit")
+			flags.StringSliceVar(&cfg.Retry.On, "retry-on", nil, "Retry status codes")
+			flags.BoolVar(&cfg.Retry.AllMethods, "retry-all-methods", false, "Retry all methods")
+			flags.DurationVar(&cfg.Timeouts.MaxTime, "max-time", 0, "Maximum time")
+			flags.DurationVar(&cfg.Timeouts.Connect, "connect-timeout", 0, "Connect timeout")
+			flags.DurationVar(&cfg.Timeouts.Spec, "spec-timeout", 30*time.Second, "Spec timeout")
+			flags.BoolVar(&cfg.CheckResponse, "check-response", false, "Check response")
 			flags.BoolVar(&cfg.Verbose, "verbose", false, "Verbose output")
 			flags.BoolVar(&cfg.IncludeHeaders, "include", false, "Include headers")
+			flags.BoolVar(&cfg.Fail, "fail", false, "Fail on HTTP errors")
+			flags.StringVar(&cfg.Output, "output", "", "Output file")
+			flags.StringVar(&cfg.MaxFilesize, "max-filesize", "", "Maximum body size")
+			flags.BoolVar(&cfg.Raw, "raw", false, "Raw output")
+			for _, language := range filter.Languages() {
+				flags.String(language, "", "Query filter")
+			}
+			flags.StringVar(&cfg.Grep, "grep", "", "Regex filter")
+			flags.IntVar(&cfg.Context, "context", 5, "Grep context")
+			flags.BoolVar(&cfg.SSE.NDJSON, "ndjson", false, "NDJSON events")
+			flags.StringVar(&cfg.SSE.LastEventID, "last-event-id", "", "Last event ID")
+			flags.BoolVar(&cfg.SSE.Reconnect, "sse-reconnect", false, "Reconnect event streams")
 			flags.BoolVar(&cfg.ShowDocs, "docs", false, "Show docs")
 			flags.BoolVar(&cfg.SigV4Enabled, "aws-sigv4", false, "Sign with SigV4")
 			flags.StringVar(&cfg.SigV4Service, "aws-service", "execute-api", "AWS service")
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile holds defaults for one environment (dev, staging, prod, ...).
+// Flags and environment variables always take precedence over profile values.
+type Profile struct {
+	OpenAPI        string            `yaml:"openapi,omitempty"`
+	Server         string            `yaml:"server,omitempty"`
+	ServerVars     map[string]string `yaml:"server-vars,omitempty"`
+	Headers        []string          `yaml:"headers,omitempty"`
+	SigV4          bool              `yaml:"aws-sigv4,omitempty"`
+	SigV4Service   string            `yaml:"aws-service,omitempty"`
+	MCPDescription string            `yaml:"mcp-desc,omitempty"`
+	Lambda         *LambdaProfile    `yaml:"lambda,omitempty"`
+}
+
+// LambdaProfile holds lambda:// event settings, mirroring the --lambda-* flags
+type LambdaProfile struct {
+	Format     string            `yaml:"format,omitempty"`
+	Endpoint   string            `yaml:"endpoint,omitempty"`
+	Stage      string            `yaml:"stage,omitempty"`
+	SourceIP   string            `yaml:"source-ip,omitempty"`
+	StageVars  map[string]string `yaml:"stage-vars,omitempty"`
+	PathParams map[string]string `yaml:"path-params,omitempty"`
+	Claims     map[string]string `yaml:"claims,omitempty"`
+	Scopes     []string          `yaml:"scopes,omitempty"`
+	Authorizer map[string]string `yaml:"authorizer,omitempty"`
+	IAMUserARN string            `yaml:"iam-arn,omitempty"`
+}
+
+// File is the qurl config file, holding named profiles
+type File struct {
+	Profiles map[string]*Profile `yaml:"profiles"`
+}
+
+// FilePath returns the location of the qurl config file:
+// $QURL_CONFIG, else $XDG_CONFIG_HOME/qurl/config.yaml, else ~/.config/qurl/config.yaml
+func FilePath() string {
+	if path := os.Getenv("QURL_CONFIG"); path != "" {
+		return path
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "qurl", "config.yaml")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "qurl", "config.yaml")
+}
+
+// LoadFile reads the qurl config file at path. A missing file yields an empty config.
+func LoadFile(path string) (*File, error) {
+	file := &File{Profiles: make(map[string]*Profile)}
+	if path == "" {
+		return file, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return file, nil
+		}
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to read config file").
+			WithContext("path", path)
+	}
+
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to parse config file").
+			WithContext("path", path)
+	}
+
+	if file.Profiles == nil {
+		file.Profiles = make(map[string]*Profile)
+	}
+
+	return file, nil
+}
+
+// ProfileNames returns the names of all profiles, sorted
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the named profile, or an error listing the available ones
+func (f *File) Profile(name string) (*Profile, error) {
+	profile, ok := f.Profiles[name]
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeConfig, "profile not found").
+			WithContext("profile", name).
+			WithContext("available_profiles", f.ProfileNames()).
+			WithContext("config_file", FilePath())
+	}
+	if profile == nil {
+		profile = &Profile{}
+	}
+	return profile, nil
+}
+
+// ProfileName returns the selected profile name from the --profile flag or QURL_PROFILE
+func ProfileName(flags *pflag.FlagSet) string {
+	if flags != nil {
+		if name, err := flags.GetString("profile"); err == nil && name != "" {
+			return name
+		}
+	}
+	return os.Getenv("QURL_PROFILE")
+}
+
+// LoadProfile loads the selected profile from the config file.
+// It returns nil when no profile is selected.
+func LoadProfile(flags *pflag.FlagSet) (*Profile, error) {
+	name := ProfileName(flags)
+	if name == "" {
+		return nil, nil
+	}
+
+	file, err := LoadFile(FilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Profile(name)
+}
+
+// applyProfile merges profile values under the config: a value is only taken
+// from the profile when neither its flag nor its environment variable was set.
+// Profile headers are prepended so -H flags can override them.
+func (c *Config) applyProfile(profile *Profile, flags *pflag.FlagSet) {
+	if profile == nil {
+		return
+	}
+
+	if c.OpenAPIURL == "" {
+		c.OpenAPIURL = profile.OpenAPI
+	}
+
+	if c.Server == "" {
+		c.Server = profile.Server
+	}
+
+	c.ServerVars = prependPairs(profile.ServerVars, c.ServerVars)
+
+	if len(profile.Headers) > 0 {
+		c.Headers = append(append([]string{}, profile.Headers...), c.Headers...)
+	}
+
+	if profile.SigV4 && !flags.Changed("aws-sigv4") {
+		c.SigV4Enabled = true
+	}
+
+	if profile.SigV4Service != "" && !flags.Changed("aws-service") {
+		c.SigV4Service = profile.SigV4Service
+	}
+
+	if c.MCP.Description == "" {
+		c.MCP.Description = profile.MCPDescription
+	}
+
+	if profile.Lambda != nil {
+		c.applyLambdaProfile(profile.Lambda)
+	}
+}
+
+// applyLambdaProfile merges the profile's lambda section. Name=value settings
+// are prepended so repeated flags with the same name override them.
+func (c *Config) applyLambdaProfile(profile *LambdaProfile) {
+	if c.LambdaFormat == "" {
+		c.LambdaFormat = profile.Format
+	}
+	if c.LambdaEndpoint == "" {
+		c.LambdaEndpoint = profile.Endpoint
+	}
+
+	lc := &c.LambdaContext
+	if lc.Stage == "" {
+		lc.Stage = profile.Stage
+	}
+	if lc.SourceIP == "" {
+		lc.SourceIP = profile.SourceIP
+	}
+	if lc.IAMUserARN == "" {
+		lc.IAMUserARN = profile.IAMUserARN
+	}
+	if len(lc.Scopes) == 0 {
+		lc.Scopes = profile.Scopes
+	}
+
+	lc.StageVars = prependPairs(profile.StageVars, lc.StageVars)
+	lc.PathParams = prependPairs(profile.PathParams, lc.PathParams)
+	lc.Claims = prependPairs(profile.Claims, lc.Claims)
+	lc.Authorizer = prependPairs(profile.Authorizer, lc.Authorizer)
+}
+
+// prependPairs returns values as sorted name=value pairs followed by pairs
+func prependPairs(values map[string]string, pairs []string) []string {
+	if len(values) == 0 {
+		return pairs
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := make([]string, 0, len(names)+len(pairs))
+	for _, name := range names {
+		merged = append(merged, name+"="+values[name])
+	}
+	return append(merged, pairs...)
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/internal/filter"
+	"github.com/spf13/pflag"
+)
+
+const profileTestFile = `profiles:
+  staging:
+    openapi: https://staging.example.com/openapi.json
+    server: https://staging.example.com
+    server-vars:
+      region: eu
+    headers:
+      - "Authorization: Bearer staging-token"
+    aws-sigv4: true
+    aws-service: lambda
+  authz:
+    server: lambda://api-fn
+    lambda:
+      format: v1
+      stage: prod
+      stage-vars:
+        table: users-prod
+      claims:
+        sub: user-1
+        cognito:groups: admin
+      scopes: [users/read]
+      authorizer:
+        tenant: acme
+      iam-arn: arn:aws:iam::210987654321:user/alice
+  prod:
+    openapi: https://api.example.com/openapi.json
+`
+
+// newProfileFlags defines the flags LoadFromFlags reads, as in main.go
+func newProfileFlags() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("profile", "", "")
+	flags.String("openapi", "", "")
+	flags.String("server", "", "")
+	flags.StringSlice("server-var", nil, "")
+	flags.String("lambda-format", "", "")
+	flags.Bool("lambda-logs", false, "")
+	flags.String("lambda-endpoint", "", "")
+	flags.String("lambda-invocation-type", "", "")
+	flags.Bool("lambda-stream", false, "")
+	flags.String("lambda-stage", "", "")
+	flags.String("lambda-source-ip", "", "")
+	flags.StringArray("lambda-stage-var", nil, "")
+	flags.StringArray("lambda-path-param", nil, "")
+	flags.StringArray("lambda-claim", nil, "")
+	flags.StringSlice("lambda-scope", nil, "")
+	flags.StringArray("lambda-authorizer", nil, "")
+	flags.String("lambda-iam-arn", "", "")
+	flags.StringSlice("request", []string{"GET"}, "")
+	flags.StringSlice("header", nil, "")
+	flags.StringSlice("query", nil, "")
+	flags.StringSlice("path-param", nil, "")
+	flags.String("data", "", "")
+	flags.String("data-raw", "", "")
+	flags.String("data-binary", "", "")
+	flags.StringArray("form", nil, "")
+	flags.Bool("validate", false, "")
+	flags.Int("retry", 0, "")
+	flags.Duration("retry-max-time", 0, "")
+	flags.StringSlice("retry-on", nil, "")
+	flags.Bool("retry-all-methods", false, "")
+	flags.Duration("max-time", 0, "")
+	flags.Duration("connect-timeout", 0, "")
+	flags.Duration("spec-timeout", 30*time.Second, "")
+	flags.Bool("check-response", false, "")
+	flags.Bool("verbose", false, "")
+	flags.Bool("include", false, "")
+	flags.Bool("fail", false, "")
+	flags.String("output", "", "")
+	flags.String("max-filesize", "", "")
+	flags.Bool("raw", false, "")
+	for _, language := range filter.Languages() {
+		flags.String(language, "", "")
+	}
+	flags.String("grep", "", "")
+	flags.Int("context", 5, "")
+	flags.Bool("ndjson", false, "")
+	flags.String("last-event-id", "", "")
+	flags.Bool("sse-reconnect", false, "")
+	flags.Bool("docs", false, "")
+	flags.Bool("aws-sigv4", false, "")
+	flags.String("aws-service", "execute-api", "")
+	flags.String("mcp-desc", "", "")
+	return flags
+}
+
+func writeProfileFile(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(profileTestFile), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("QURL_CONFIG", path)
+	t.Setenv("QURL_PROFILE", "")
+	t.Setenv("QURL_OPENAPI", "")
+	t.Setenv("QURL_SERVER", "")
+}
+
+func TestLoadFromFlags_Profile(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		flags    map[string]string
+		expected func(t *testing.T, c *Config)
+	}{
+		{
+			name:  "profile fills unset values",
+			flags: map[string]string{"profile": "staging"},
+			expected: func(t *testing.T, c *Config) {
+				if c.OpenAPIURL != "https://staging.example.com/openapi.json" {
+					t.Errorf("OpenAPIURL = %q", c.OpenAPIURL)
+				}
+				if c.Server != "https://staging.example.com" {
+					t.Errorf("Server = %q", c.Server)
+				}
+				if strings.Join(c.ServerVars, ",") != "region=eu" {
+					t.Errorf("ServerVars = %v", c.ServerVars)
+				}
+				if !c.SigV4Enabled || c.SigV4Service != "lambda" {
+					t.Errorf("SigV4 = %v/%q", c.SigV4Enabled, c.SigV4Service)
+				}
+				if c.MCP.OpenAPIURL != c.OpenAPIURL || len(c.MCP.Headers) != 1 {
+					t.Errorf("Profile values not propagated to MCP config: %+v", c.MCP)
+				}
+			},
+		},
+		{
+			name: "QURL_PROFILE selects the profile",
+			env:  map[string]string{"QURL_PROFILE": "prod"},
+			expected: func(t *testing.T, c *Config) {
+				if c.Profile != "prod" || c.OpenAPIURL != "https://api.example.com/openapi.json" {
+					t.Errorf("Profile %q, OpenAPIURL %q", c.Profile, c.OpenAPIURL)
+				}
+			},
+		},
+		{
+			name:  "flag beats environment beats profile",
+			env:   map[string]string{"QURL_PROFILE": "prod", "QURL_SERVER": "https://env.example.com"},
+			flags: map[string]string{"profile": "staging", "openapi": "https://flag.example.com/openapi.json", "aws-service": "execute-api"},
+			expected: func(t *testing.T, c *Config) {
+				if c.Profile != "staging" {
+					t.Errorf("Profile = %q, expected flag to win", c.Profile)
+				}
+				if c.OpenAPIURL != "https://flag.example.com/openapi.json" {
+					t.Errorf("OpenAPIURL = %q", c.OpenAPIURL)
+				}
+				if c.Server != "https://env.example.com" {
+					t.Errorf("Server = %q", c.Server)
+				}
+				if c.SigV4Service != "execute-api" {
+					t.Errorf("SigV4Service = %q", c.SigV4Service)
+				}
+			},
+		},
+		{
+			name:  "profile headers come before -H headers",
+			flags: map[string]string{"profile": "staging", "header": "Authorization: Bearer mine"},
+			expected: func(t *testing.T, c *Config) {
+				expected := "Authorization: Bearer staging-token,Authorization: Bearer mine"
+				if strings.Join(c.Headers, ",") != expected {
+					t.Errorf("Headers = %v", c.Headers)
+				}
+			},
+		},
+		{
+			name:  "lambda section fills the event context",
+			flags: map[string]string{"profile": "authz", "lambda-claim": "sub=user-2", "lambda-stage": "dev"},
+			expected: func(t *testing.T, c *Config) {
+				lc := c.LambdaContext
+				if c.LambdaFormat != "v1" || lc.Stage != "dev" || lc.IAMUserARN != "arn:aws:iam::210987654321:user/alice" {
+					t.Errorf("LambdaFormat = %q, Stage = %q, IAMUserARN = %q", c.LambdaFormat, lc.Stage, lc.IAMUserARN)
+				}
+				if strings.Join(lc.Claims, ",") != "cognito:groups=admin,sub=user-1,sub=user-2" {
+					t.Errorf("Claims = %v, expected the flag after the profile", lc.Claims)
+				}
+				if strings.Join(lc.StageVars, ",") != "table=users-prod" || strings.Join(lc.Authorizer, ",") != "tenant=acme" {
+					t.Errorf("StageVars = %v, Authorizer = %v", lc.StageVars, lc.Authorizer)
+				}
+				if strings.Join(lc.Scopes, ",") != "users/read" {
+					t.Errorf("Scopes = %v", lc.Scopes)
+				}
+			},
+		},
+		{
+			name: "no profile selected",
+			expected: func(t *testing.T, c *Config) {
+				if c.OpenAPIURL != "" || c.Server != "" {
+					t.Errorf("Expected no profile values, got %q %q", c.OpenAPIURL, c.Server)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeProfileFile(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			flags := newProfileFlags()
+			for name, value := range tt.flags {
+				if err := flags.Set(name, value); err != nil {
+					t.Fatalf("Failed to set flag %s: %v", name, err)
+				}
+			}
+
+			cfg, err := LoadFromFlags(flags)
+			if err != nil {
+				t.Fatalf("LoadFromFlags failed: %v", err)
+			}
+			tt.expected(t, cfg)
+		})
+	}
+}
+
+func TestLoadFromFlags_UnknownProfile(t *testing.T) {
+	writeProfileFile(t)
+
+	flags := newProfileFlags()
+	flags.Set("profile", "qa")
+
+	_, err := LoadFromFlags(flags)
+	if !errors.IsType(err, errors.ErrorTypeConfig) {
+		t.Fatalf("Expected config error, got %v", err)
+	}
+	if available := errors.GetContext(err)["available_profiles"]; strings.Join(available.([]string), ",") != "authz,prod,staging" {
+		t.Errorf("Expected available profiles authz,prod,staging, got %v", available)
+	}
+}
+
+func TestFilePath(t *testing.T) {
+	t.Setenv("QURL_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	if path := FilePath(); path != "/tmp/xdg/qurl/config.yaml" {
+		t.Errorf("FilePath() = %q", path)
+	}
+
+	t.Setenv("QURL_CONFIG", "/etc/qurl.yaml")
+	if path := FilePath(); path != "/etc/qurl.yaml" {
+		t.Errorf("FilePath() = %q", path)
+	}
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	file, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Expected missing file to be ignored, got %v", err)
+	}
+	if len(file.ProfileNames()) != 0 {
+		t.Errorf("Expected no profiles, got %v", file.ProfileNames())
+	}
+}
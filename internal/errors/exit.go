@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+)
+
+// Exit codes returned by qurl. Each error type has its own code so scripts
+// can tell failure classes apart; the HTTP, timeout and interrupt codes match
+// curl and the shell.
+const (
+	ExitOK          = 0   // Success
+	ExitInternal    = 1   // Internal or unclassified error
+	ExitConfig      = 2   // Invalid configuration or command line
+	ExitValidation  = 3   // Request failed validation, including against the OpenAPI spec
+	ExitNetwork     = 4   // Connection or transport failure
+	ExitAuth        = 5   // Authentication or signing failure
+	ExitOpenAPI     = 6   // OpenAPI spec could not be loaded or used
+	ExitMCP         = 7   // MCP server failure
+	ExitLambda      = 8   // Lambda invocation or function error
+	ExitHTTP        = 22  // HTTP status 400 or above with --fail, as curl --fail
+	ExitTimeout     = 28  // --max-time or another time limit exceeded, as curl
+	ExitInterrupted = 130 // Cancelled by Ctrl-C
+)
+
+// exitCodes maps error types to their exit code
+var exitCodes = map[ErrorType]int{
+	ErrorTypeInternal:   ExitInternal,
+	ErrorTypeConfig:     ExitConfig,
+	ErrorTypeValidation: ExitValidation,
+	ErrorTypeNetwork:    ExitNetwork,
+	ErrorTypeAuth:       ExitAuth,
+	ErrorTypeOpenAPI:    ExitOpenAPI,
+	ErrorTypeMCP:        ExitMCP,
+	ErrorTypeLambda:     ExitLambda,
+	ErrorTypeHTTP:       ExitHTTP,
+}
+
+// ExitCode returns the process exit code for err. Cancellation and timeouts
+// take precedence over the error type; errors that are not a QUrlError
+// exit with ExitInternal.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return ExitInterrupted
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	}
+
+	var qErr *QUrlError
+	if stderrors.As(err, &qErr) {
+		if code, ok := exitCodes[qErr.Type]; ok {
+			return code
+		}
+	}
+	return ExitInternal
+}
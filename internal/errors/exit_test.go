@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"config", New(ErrorTypeConfig, "bad profile"), ExitConfig},
+		{"validation", New(ErrorTypeValidation, "invalid method"), ExitValidation},
+		{"network", Wrap(fmt.Errorf("connection refused"), ErrorTypeNetwork, "HTTP request failed"), ExitNetwork},
+		{"auth", New(ErrorTypeAuth, "no credentials"), ExitAuth},
+		{"openapi", New(ErrorTypeOpenAPI, "spec not found"), ExitOpenAPI},
+		{"mcp", New(ErrorTypeMCP, "bad message"), ExitMCP},
+		{"lambda", New(ErrorTypeLambda, "function error"), ExitLambda},
+		{"http", New(ErrorTypeHTTP, "server returned 500"), ExitHTTP},
+		{"internal", New(ErrorTypeInternal, "unexpected"), ExitInternal},
+		{"wrapped", fmt.Errorf("executing: %w", New(ErrorTypeAuth, "no credentials")), ExitAuth},
+		{"plain error", fmt.Errorf("unknown flag: --nope"), ExitInternal},
+		{"timed out", Wrap(context.DeadlineExceeded, ErrorTypeNetwork, "request timed out"), ExitTimeout},
+		{"interrupted", Wrap(context.Canceled, ErrorTypeNetwork, "request cancelled"), ExitInterrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, expected %d", got, tt.want)
+			}
+		})
+	}
+}
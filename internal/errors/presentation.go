@@ -29,6 +29,8 @@ func formatUserError(qErr *QUrlError) string {
 		return formatOpenAPIError(qErr)
 	case ErrorTypeMCP:
 		return formatMCPError(qErr)
+	case ErrorTypeLambda:
+		return formatLambdaError(qErr)
 	default:
 		return qErr.Message
 	}
@@ -72,7 +74,8 @@ func formatOpenAPIError(qErr *QUrlError) string {
 	return qErr.Message
 }
 
-// PresentError displays an error to the user through centralized zerolog system
+// PresentError displays an error to the user through centralized zerolog system.
+// It does not exit; the caller exits with ExitCode(err).
 func PresentError(err error) {
 	if err == nil {
 		return
@@ -80,7 +83,7 @@ func PresentError(err error) {
 
 	// Use the global logger
 	if qErr, ok := err.(*QUrlError); ok {
-		event := log.Fatal()
+		event := log.Error()
 
 		// Add context fields as structured data
 		for key, value := range qErr.Context {
@@ -89,7 +92,7 @@ func PresentError(err error) {
 
 		event.Msg(qErr.Message)
 	} else {
-		log.Fatal().Err(err).Msg("")
+		log.Error().Err(err).Msg("")
 	}
 }
 
@@ -97,6 +100,15 @@ func formatMCPError(qErr *QUrlError) string {
 	return qErr.Message
 }
 
+func formatLambdaError(qErr *QUrlError) string {
+	msg := qErr.Message
+	if function, ok := qErr.Context["function"]; ok {
+		msg = fmt.Sprintf("Lambda %s: %s", function, msg)
+	}
+
+	return msg
+}
+
 // DebugInfo returns detailed error information for debugging
 func DebugInfo(err error) map[string]interface{} {
 	info := map[string]interface{}{
@@ -15,6 +15,8 @@ const (
 	ErrorTypeInternal   ErrorType = "internal"
 	ErrorTypeOpenAPI    ErrorType = "openapi"
 	ErrorTypeMCP        ErrorType = "mcp"
+	ErrorTypeLambda     ErrorType = "lambda"
+	ErrorTypeHTTP       ErrorType = "http" // Error status returned with --fail
 )
 
 // QUrlError represents a structured error with context
@@ -1,4 +1,7 @@
-package mcp
+// Package filter extracts parts of response bodies with regular expressions or
+// with a JSON query language: JMESPath, JSONPath or jq. It is
+// shared by the CLI and the MCP server.
+package filter
 
 import (
 	"encoding/json"
@@ -6,23 +9,22 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/jmespath/go-jmespath"
 	"github.com/rs/zerolog/log"
 )
 
-// FilterResult represents the result of a filtering operation
-type FilterResult struct {
+// Result represents the result of a filtering operation
+type Result struct {
 	Content string                 `json:"content"`
 	Meta    map[string]interface{} `json:"_meta"`
 }
 
-// estimateTokens approximates token count using chars/4 heuristic
-func estimateTokens(data string) int {
+// EstimateTokens approximates token count using chars/4 heuristic
+func EstimateTokens(data string) int {
 	return len(data) / 4
 }
 
-// filterRegex searches text using regex and returns matches with context characters
-func filterRegex(body string, pattern string, contextLines int) (*FilterResult, error) {
+// Regex searches text using regex and returns matches with context characters
+func Regex(body string, pattern string, contextLines int) (*Result, error) {
 	re, err := regexp.Compile(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("invalid regex pattern: %w", err)
@@ -39,15 +41,15 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 		Str("pattern", pattern).
 		Int("context_lines", contextLines).
 		Int("context_chars", contextChars).
-		Msg("filterRegex: starting")
+		Msg("filter.Regex: starting")
 
 	// Find all matches
 	matches := re.FindAllStringIndex(body, -1)
 	matchCount := len(matches)
 
 	if matchCount == 0 {
-		log.Debug().Msg("filterRegex: no matches found")
-		return &FilterResult{
+		log.Debug().Msg("filter.Regex: no matches found")
+		return &Result{
 			Content: "",
 			Meta: map[string]interface{}{
 				"filter": map[string]interface{}{
@@ -57,7 +59,7 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 				},
 				"tokens": map[string]interface{}{
 					"returned": 0,
-					"source":   estimateTokens(body),
+					"source":   EstimateTokens(body),
 				},
 				"bytes": map[string]interface{}{
 					"returned": 0,
@@ -69,7 +71,7 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 
 	log.Debug().
 		Int("total_matches", matchCount).
-		Msg("filterRegex: found matches")
+		Msg("filter.Regex: found matches")
 
 	// Build context windows for each match
 	type contextWindow struct {
@@ -93,7 +95,7 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 			Int("window_start", windowStart).
 			Int("window_end", windowEnd).
 			Str("matched_text", body[matchStart:matchEnd]).
-			Msg("filterRegex: processing match")
+			Msg("filter.Regex: processing match")
 
 		windows = append(windows, contextWindow{start: windowStart, end: windowEnd})
 	}
@@ -110,7 +112,7 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 			log.Debug().
 				Int("merged_start", last.start).
 				Int("merged_end", last.end).
-				Msg("filterRegex: merged overlapping windows")
+				Msg("filter.Regex: merged overlapping windows")
 		} else {
 			merged = append(merged, curr)
 		}
@@ -119,7 +121,7 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 	log.Debug().
 		Int("original_windows", len(windows)).
 		Int("merged_windows", len(merged)).
-		Msg("filterRegex: finished merging")
+		Msg("filter.Regex: finished merging")
 
 	// Build output from merged windows
 	var matchBlocks []string
@@ -146,9 +148,9 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 		Int("result_bytes", len(resultContent)).
 		Int("input_bytes", len(body)).
 		Float64("reduction_pct", 100.0*float64(len(body)-len(resultContent))/float64(len(body))).
-		Msg("filterRegex: result created")
+		Msg("filter.Regex: result created")
 
-	return &FilterResult{
+	return &Result{
 		Content: resultContent,
 		Meta: map[string]interface{}{
 			"filter": map[string]interface{}{
@@ -158,8 +160,8 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 				"merged_windows": len(merged),
 			},
 			"tokens": map[string]interface{}{
-				"returned": estimateTokens(resultContent),
-				"source":   estimateTokens(body),
+				"returned": EstimateTokens(resultContent),
+				"source":   EstimateTokens(body),
 			},
 			"bytes": map[string]interface{}{
 				"returned": len(resultContent),
@@ -169,53 +171,95 @@ func filterRegex(body string, pattern string, contextLines int) (*FilterResult,
 	}, nil
 }
 
-// filterJMESPath filters JSON using a JMESPath expression
-func filterJMESPath(body string, expression string) (*FilterResult, error) {
+// Compile checks an expression in the named query language, so a typo can be
+// reported before the request is sent
+func Compile(language string, expression string) error {
+	lang, err := Lookup(language)
+	if err != nil {
+		return err
+	}
+	if err := lang.Compile(expression); err != nil {
+		return fmt.Errorf("invalid %s expression: %w", language, err)
+	}
+	return nil
+}
+
+// Search filters a JSON body with an expression in the named query language.
+// Every language reports the same _meta, so results can be compared.
+func Search(language string, body string, expression string) (*Result, error) {
+	lang, err := Lookup(language)
+	if err != nil {
+		return nil, err
+	}
+
 	var data interface{}
 	if err := json.Unmarshal([]byte(body), &data); err != nil {
 		return nil, fmt.Errorf("invalid JSON response: %w", err)
 	}
 
-	result, err := jmespath.Search(expression, data)
+	result, err := lang.Search(expression, data)
 	if err != nil {
-		return nil, fmt.Errorf("invalid jmespath expression: %w", err)
+		return nil, fmt.Errorf("invalid %s expression: %w", language, err)
 	}
 
-	filteredJSON, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal filtered result: %w", err)
+	// A stream is written one value after another, as jq prints it; a single
+	// value is written like any other result
+	var values []interface{}
+	if stream, ok := result.(Stream); ok && len(stream) != 1 {
+		values = stream
+	} else {
+		if ok {
+			result = stream[0]
+		}
+		values = []interface{}{result}
+	}
+
+	var parts []string
+	for _, value := range values {
+		filteredJSON, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal filtered result: %w", err)
+		}
+		parts = append(parts, string(filteredJSON))
 	}
 
 	// Count results
-	resultCount := 0
-	if arr, ok := result.([]interface{}); ok {
-		resultCount = len(arr)
-	} else if result != nil {
-		resultCount = 1
+	resultCount := len(values)
+	if len(values) == 1 {
+		if arr, ok := result.([]interface{}); ok {
+			resultCount = len(arr)
+		} else if result == nil {
+			resultCount = 0
+		}
 	}
 
-	resultContent := string(filteredJSON)
+	resultContent := strings.Join(parts, "\n")
 
-	return &FilterResult{
+	return &Result{
 		Content: resultContent,
 		Meta: map[string]interface{}{
 			"filter": map[string]interface{}{
-				"type":         "jmespath",
+				"type":         language,
 				"expression":   expression,
 				"result_count": resultCount,
 			},
 			"tokens": map[string]interface{}{
-				"returned": estimateTokens(resultContent),
-				"source":   estimateTokens(body),
+				"returned": EstimateTokens(resultContent),
+				"source":   EstimateTokens(body),
 			},
 			"bytes": map[string]interface{}{
-				"returned": len(filteredJSON),
+				"returned": len(resultContent),
 				"source":   len(body),
 			},
 		},
 	}, nil
 }
 
+// JMESPath filters JSON using a JMESPath expression
+func JMESPath(body string, expression string) (*Result, error) {
+	return Search("jmespath", body, expression)
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -1,4 +1,4 @@
-package mcp
+package filter
 
 import (
 	"encoding/json"
@@ -31,9 +31,9 @@ func TestEstimateTokens(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := estimateTokens(tt.input)
+			result := EstimateTokens(tt.input)
 			if result != tt.expected {
-				t.Errorf("estimateTokens() = %d, want %d", result, tt.expected)
+				t.Errorf("EstimateTokens() = %d, want %d", result, tt.expected)
 			}
 		})
 	}
@@ -132,17 +132,17 @@ status: failed`,
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := filterRegex(tt.body, tt.pattern, tt.contextLines)
+			result, err := Regex(tt.body, tt.pattern, tt.contextLines)
 
 			if tt.wantError {
 				if err == nil {
-					t.Errorf("filterRegex() expected error, got nil")
+					t.Errorf("Regex() expected error, got nil")
 				}
 				return
 			}
 
 			if err != nil {
-				t.Errorf("filterRegex() unexpected error: %v", err)
+				t.Errorf("Regex() unexpected error: %v", err)
 				return
 			}
 
@@ -160,10 +160,10 @@ status: failed`,
 
 			// Check tokens metadata
 			tokens := result.Meta["tokens"].(map[string]interface{})
-			if tokens["source"] != estimateTokens(tt.body) {
+			if tokens["source"] != EstimateTokens(tt.body) {
 				t.Errorf("source tokens mismatch")
 			}
-			if tokens["returned"] != estimateTokens(result.Content) {
+			if tokens["returned"] != EstimateTokens(result.Content) {
 				t.Errorf("returned tokens mismatch")
 			}
 
@@ -262,17 +262,17 @@ func TestFilterJMESPath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := filterJMESPath(tt.body, tt.expression)
+			result, err := JMESPath(tt.body, tt.expression)
 
 			if tt.wantError {
 				if err == nil {
-					t.Errorf("filterJMESPath() expected error, got nil")
+					t.Errorf("JMESPath() expected error, got nil")
 				}
 				return
 			}
 
 			if err != nil {
-				t.Errorf("filterJMESPath() unexpected error: %v", err)
+				t.Errorf("JMESPath() unexpected error: %v", err)
 				return
 			}
 
@@ -290,10 +290,10 @@ func TestFilterJMESPath(t *testing.T) {
 
 			// Check tokens metadata
 			tokens := result.Meta["tokens"].(map[string]interface{})
-			if tokens["source"] != estimateTokens(tt.body) {
+			if tokens["source"] != EstimateTokens(tt.body) {
 				t.Errorf("source tokens mismatch")
 			}
-			if tokens["returned"] != estimateTokens(result.Content) {
+			if tokens["returned"] != EstimateTokens(result.Content) {
 				t.Errorf("returned tokens mismatch")
 			}
 
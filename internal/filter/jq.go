@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"github.com/itchyny/gojq"
+)
+
+func init() {
+	Register(jqLanguage{})
+}
+
+// jqLanguage evaluates jq programs (https://jqlang.org) with gojq, a pure Go
+// implementation of the language. Object keys are iterated in sorted order,
+// and builtins that read input or the environment are not available.
+type jqLanguage struct{}
+
+func (jqLanguage) Name() string { return "jq" }
+
+func (jqLanguage) DisplayName() string { return "jq" }
+
+func (jqLanguage) Example() string { return ".[] | .name" }
+
+func (jqLanguage) Description() string {
+	return `jq program to filter JSON response (https://jqlang.org). Several outputs are returned one per line, as jq prints them.
+
+The full jq language is available, including variables, reduce, def and string interpolation. Builtins that read input or the environment (input, env, ...) are not.
+
+**Examples:**
+- '.items[].name' - the name of each item
+- '[.items[] | select(.price > 100) | {id, name}]' - matching items with only id and name
+- '.items | length' - count the items`
+}
+
+func (jqLanguage) Compile(expression string) error {
+	_, err := compileJQ(expression)
+	return err
+}
+
+func (jqLanguage) Search(expression string, data interface{}) (interface{}, error) {
+	code, err := compileJQ(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := Stream{}
+	iter := code.Run(data)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := value.(error); ok {
+			// halt stops the program without an error
+			if halt, ok := err.(*gojq.HaltError); ok && halt.Value() == nil {
+				break
+			}
+			return nil, err
+		}
+		outputs = append(outputs, value)
+	}
+	return outputs, nil
+}
+
+// compileJQ parses and compiles a jq program
+func compileJQ(expression string) (*gojq.Code, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	return gojq.Compile(query)
+}
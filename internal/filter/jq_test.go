@@ -0,0 +1,143 @@
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJQ(t *testing.T) {
+	body := `{
+		"store": {"name": "Pets and Co", "open": true},
+		"pets": [
+			{"id": 1, "name": "doggie", "status": "available", "tags": ["good", "loud"], "price": 30},
+			{"id": 2, "name": "kitty", "status": "sold", "tags": [], "price": 20},
+			{"id": 3, "name": "nemo", "status": "available", "tags": ["wet"], "price": 5}
+		]
+	}`
+
+	tests := []struct {
+		expression string
+		want       []string // Each output, as compact JSON
+	}{
+		{`.store.name`, []string{`"Pets and Co"`}},
+		{`."store"."open"`, []string{`true`}},
+		{`.store["name"]`, []string{`"Pets and Co"`}},
+		{`.missing.deeper`, []string{`null`}},
+		{`.pets[0].id`, []string{`1`}},
+		{`.pets[-1].name`, []string{`"nemo"`}},
+		{`.pets[1:].[].id`, []string{`2`, `3`}},
+		{`.pets[:1] | length`, []string{`1`}},
+		{`.pets[].name`, []string{`"doggie"`, `"kitty"`, `"nemo"`}},
+		{`[.pets[].name]`, []string{`["doggie","kitty","nemo"]`}},
+		{`.pets[] | select(.status == "available") | .id`, []string{`1`, `3`}},
+		{`.pets | map(select(.price > 10) | .name)`, []string{`["doggie","kitty"]`}},
+		{`.pets[0] | {id, label: .name, "n": (.tags | length)}`, []string{`{"id":1,"label":"doggie","n":2}`}},
+		{`.pets[0] | {(.name): .price}`, []string{`{"doggie":30}`}},
+		{`.pets[0].id, .pets[1].id`, []string{`1`, `2`}},
+		{`[.pets[].price] | add`, []string{`55`}},
+		{`.pets | map(.price) | add / length`, []string{`18.333333333333332`}},
+		{`.pets[0].price - 10 * 2`, []string{`10`}},
+		{`-.pets[0].id`, []string{`-1`}},
+		{`.pets | sort_by(.price) | map(.name)`, []string{`["nemo","kitty","doggie"]`}},
+		{`.pets | group_by(.status) | map(length)`, []string{`[2,1]`}},
+		{`.pets | unique_by(.status) | map(.id)`, []string{`[1,2]`}},
+		{`.pets | max_by(.price).name`, []string{`"doggie"`}},
+		{`.pets | min_by(.price).name`, []string{`"nemo"`}},
+		{`.store | keys`, []string{`["name","open"]`}},
+		{`.store | to_entries | map(.key)`, []string{`["name","open"]`}},
+		{`.store | with_entries(select(.key == "open"))`, []string{`{"open":true}`}},
+		{`.store | has("name"), has("owner")`, []string{`true`, `false`}},
+		{`.pets[0].tags | join(", ")`, []string{`"good, loud"`}},
+		{`.pets[0].tags | contains(["loud"])`, []string{`true`}},
+		{`.pets[] | select(.name | test("^k")) | .id`, []string{`2`}},
+		{`.pets[] | select(.name | startswith("ne")) | .name | ascii_upcase`, []string{`"NEMO"`}},
+		{`.pets[] | if .price > 25 then "dear" elif .price > 10 then "fair" else "cheap" end`, []string{`"dear"`, `"fair"`, `"cheap"`}},
+		{`.pets[] | select(.status == "sold" and .price < 30) | .id`, []string{`2`}},
+		{`.pets[] | select(.tags | length == 0 or .[0] == "wet") | .id`, []string{`2`, `3`}},
+		{`.store.owner // "nobody"`, []string{`"nobody"`}},
+		{`.store.name | type`, []string{`"string"`}},
+		{`.store.name.first?`, []string{}},
+		{`try .store.name.first`, []string{}},
+		{`[.. | .id? // empty]`, []string{`[1,2,3]`}},
+		{`[.pets[].tags[]] | unique`, []string{`["good","loud","wet"]`}},
+		{`[limit(2; .pets[].id)]`, []string{`[1,2]`}},
+		{`[range(3)]`, []string{`[0,1,2]`}},
+		{`first(.pets[].name)`, []string{`"doggie"`}},
+		{`.pets | first.id, last.id`, []string{`1`, `3`}},
+		{`.pets | any(.price < 10), all(.price < 10)`, []string{`true`, `false`}},
+		{`"a,b" / ","`, []string{`["a","b"]`}},
+		{`.pets[0].id | tostring`, []string{`"1"`}},
+		{`[.pets[].tags] | flatten | length`, []string{`3`}},
+		{`.pets | map(.id) | reverse`, []string{`[3,2,1]`}},
+		{`.pets[] | select(.id == 4)`, []string{}},
+		{`empty`, []string{}},
+		{`.pets[0] as $p | $p.name`, []string{`"doggie"`}},
+		{`reduce .pets[] as $p (0; . + $p.price)`, []string{`55`}},
+		{`def cheap: select(.price < 25); [.pets[] | cheap | .id]`, []string{`[2,3]`}},
+		{`.pets[] | "\(.name) costs \(.price)"`, []string{`"doggie costs 30"`, `"kitty costs 20"`, `"nemo costs 5"`}},
+		{`[.pets[] | [.id, .name]] | map(@csv)`, []string{`["1,\"doggie\"","2,\"kitty\"","3,\"nemo\""]`}},
+		{`.store.open = false | .store`, []string{`{"name":"Pets and Co","open":false}`}},
+		{`.pets[0] | .price |= . * 2 | .price`, []string{`60`}},
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			result, err := jqLanguage{}.Search(tt.expression, data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			outputs := result.(Stream)
+			got := make([]string, len(outputs))
+			for i, output := range outputs {
+				encoded, _ := json.Marshal(output)
+				got[i] = string(encoded)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d outputs %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("output %d: got %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestJQErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"unclosed bracket", `.pets[`},
+		{"trailing operator", `.a |`},
+		{"unknown function", `frobnicate`},
+		{"wrong arity", `map`},
+		{"undefined variable", `$x`},
+		{"unknown format", `@frob`},
+		{"error", `error("boom")`},
+		{"index a string", `.store.name.first`},
+		{"iterate a number", `.pets[0].id[]`},
+		{"add a string and a number", `.store.name + 1`},
+		{"divide by zero", `1 / 0`},
+	}
+
+	data := map[string]interface{}{
+		"store": map[string]interface{}{"name": "Pets"},
+		"pets":  []interface{}{map[string]interface{}{"id": 1.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (jqLanguage{}).Search(tt.expression, data); err == nil {
+				t.Errorf("expected an error for %q", tt.expression)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package filter
+
+import (
+	"github.com/pb33f/jsonpath/pkg/jsonpath"
+	"go.yaml.in/yaml/v4"
+)
+
+func init() {
+	Register(jsonpathLanguage{})
+}
+
+// jsonpathLanguage evaluates JSONPath queries (RFC 9535) with the library
+// libopenapi uses. A query returns a list of the nodes it selects, so the
+// result is always an array.
+type jsonpathLanguage struct{}
+
+func (jsonpathLanguage) Name() string { return "jsonpath" }
+
+func (jsonpathLanguage) DisplayName() string { return "JSONPath" }
+
+func (jsonpathLanguage) Example() string { return "$[*].name" }
+
+func (jsonpathLanguage) Description() string {
+	return `JSONPath query to filter JSON response (RFC 9535). The result is always an array of the selected nodes.
+
+**Examples:**
+- '$.items[*].name' - extract just the name field from an array
+- '$.items[?(@.price > 100)]' - filter items by condition
+- '$..id' - every id field at any depth`
+}
+
+func (jsonpathLanguage) Compile(expression string) error {
+	_, err := jsonpath.NewPath(expression)
+	return err
+}
+
+func (jsonpathLanguage) Search(expression string, data interface{}) (interface{}, error) {
+	path, err := jsonpath.NewPath(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := root.Encode(data); err != nil {
+		return nil, err
+	}
+
+	nodes := []interface{}{}
+	for _, node := range path.Query(&root) {
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, value)
+	}
+	return nodes, nil
+}
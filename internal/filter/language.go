@@ -0,0 +1,92 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+)
+
+// Language is a query language for JSON response bodies. Implementations
+// register themselves with Register and are selected by name with Search.
+type Language interface {
+	// Name identifies the language in CLI flags, MCP tool parameters and _meta
+	Name() string
+	// DisplayName is the language's name in help text and error messages
+	DisplayName() string
+	// Example is a short expression shown in the CLI flag help
+	Example() string
+	// Description explains the language and its results to MCP clients
+	Description() string
+	// Compile checks expression for syntax errors without evaluating it
+	Compile(expression string) error
+	// Search evaluates expression against a body decoded by encoding/json
+	Search(expression string, data interface{}) (interface{}, error)
+}
+
+// Stream is a result of several separate values, such as the outputs of a
+// jq program. Search writes them one after another rather than as an array.
+type Stream []interface{}
+
+// languages holds the registered languages by name
+var languages = map[string]Language{}
+
+// Register makes a language available to Search under its name
+func Register(language Language) {
+	languages[language.Name()] = language
+}
+
+// Lookup returns the registered language with the given name
+func Lookup(name string) (Language, error) {
+	language, ok := languages[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter language %q (available: %s)", name, strings.Join(Languages(), ", "))
+	}
+	return language, nil
+}
+
+// Languages returns the names of the registered languages in sorted order
+func Languages() []string {
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(jmespathLanguage{})
+}
+
+// jmespathLanguage evaluates JMESPath expressions (https://jmespath.org)
+type jmespathLanguage struct{}
+
+func (jmespathLanguage) Name() string { return "jmespath" }
+
+func (jmespathLanguage) DisplayName() string { return "JMESPath" }
+
+func (jmespathLanguage) Example() string { return "[].name" }
+
+func (jmespathLanguage) Description() string {
+	return `JMESPath expression to filter JSON response (https://jmespath.org).
+
+**STRONGLY RECOMMENDED** when working with JSON responses and you only need specific fields. This dramatically reduces token usage.
+
+**Examples:**
+- 'items[].name' - extract just the name field from an array
+- 'data.{id: id, name: name}' - extract only id and name fields
+- 'items[?price > 100]' - filter items by condition
+
+**When to use:** Always prefer this for JSON responses when you need specific fields rather than the entire response.`
+}
+
+func (jmespathLanguage) Compile(expression string) error {
+	_, err := jmespath.Compile(expression)
+	return err
+}
+
+func (jmespathLanguage) Search(expression string, data interface{}) (interface{}, error) {
+	return jmespath.Search(expression, data)
+}
@@ -0,0 +1,206 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLanguages(t *testing.T) {
+	want := []string{"jmespath", "jq", "jsonpath"}
+	if got := Languages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Languages() = %v, want %v", got, want)
+	}
+
+	// Flags and MCP parameters are generated from each language's own help
+	for _, name := range Languages() {
+		language, err := Lookup(name)
+		if err != nil {
+			t.Fatalf("Lookup(%q) error: %v", name, err)
+		}
+		if language.DisplayName() == "" || language.Example() == "" || language.Description() == "" {
+			t.Errorf("%s: display name, example and description are required", name)
+		}
+		if err := language.Compile(language.Example()); err != nil {
+			t.Errorf("%s: example %q does not compile: %v", name, language.Example(), err)
+		}
+	}
+
+	if _, err := Lookup("xpath"); err == nil || !strings.Contains(err.Error(), "unknown filter language") {
+		t.Errorf("Lookup(xpath) error = %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	body := `{"pets": [{"name": "doggie", "status": "available"}, {"name": "kitty", "status": "sold"}]}`
+
+	tests := []struct {
+		name        string
+		language    string
+		expression  string
+		wantContent string
+		wantCount   int
+		wantError   string
+	}{
+		{
+			name:        "jmespath projection",
+			language:    "jmespath",
+			expression:  "pets[].name",
+			wantContent: "[\n  \"doggie\",\n  \"kitty\"\n]",
+			wantCount:   2,
+		},
+		{
+			name:        "jsonpath wildcard",
+			language:    "jsonpath",
+			expression:  "$.pets[*].name",
+			wantContent: "[\n  \"doggie\",\n  \"kitty\"\n]",
+			wantCount:   2,
+		},
+		{
+			name:        "jsonpath filter",
+			language:    "jsonpath",
+			expression:  "$.pets[?(@.status == 'sold')].name",
+			wantContent: "[\n  \"kitty\"\n]",
+			wantCount:   1,
+		},
+		{
+			name:        "jsonpath recursive descent",
+			language:    "jsonpath",
+			expression:  "$..status",
+			wantContent: "[\n  \"available\",\n  \"sold\"\n]",
+			wantCount:   2,
+		},
+		{
+			name:        "jsonpath without matches",
+			language:    "jsonpath",
+			expression:  "$.owners[*]",
+			wantContent: "[]",
+			wantCount:   0,
+		},
+		{
+			name:        "jq array construction",
+			language:    "jq",
+			expression:  "[.pets[].name]",
+			wantContent: "[\n  \"doggie\",\n  \"kitty\"\n]",
+			wantCount:   2,
+		},
+		{
+			name:        "jq stream is written one value per line",
+			language:    "jq",
+			expression:  ".pets[].name",
+			wantContent: "\"doggie\"\n\"kitty\"",
+			wantCount:   2,
+		},
+		{
+			name:        "jq single value",
+			language:    "jq",
+			expression:  ".pets[0] | {name}",
+			wantContent: "{\n  \"name\": \"doggie\"\n}",
+			wantCount:   1,
+		},
+		{
+			name:        "jq without outputs",
+			language:    "jq",
+			expression:  ".pets[] | select(.status == \"lost\")",
+			wantContent: "",
+			wantCount:   0,
+		},
+		{
+			name:       "unknown language",
+			language:   "xpath",
+			expression: "//name",
+			wantError:  "unknown filter language",
+		},
+		{
+			name:       "invalid jsonpath",
+			language:   "jsonpath",
+			expression: "$.pets[",
+			wantError:  "invalid jsonpath expression",
+		},
+		{
+			name:       "invalid jq",
+			language:   "jq",
+			expression: ".pets[",
+			wantError:  "invalid jq expression",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Search(tt.language, body, tt.expression)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("Search() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Search() unexpected error: %v", err)
+			}
+
+			if result.Content != tt.wantContent {
+				t.Errorf("content = %q, want %q", result.Content, tt.wantContent)
+			}
+
+			// Every language reports the same _meta
+			filterMeta := result.Meta["filter"].(map[string]interface{})
+			if filterMeta["type"] != tt.language {
+				t.Errorf("filter type = %v, want %s", filterMeta["type"], tt.language)
+			}
+			if filterMeta["expression"] != tt.expression {
+				t.Errorf("filter expression = %v, want %s", filterMeta["expression"], tt.expression)
+			}
+			if filterMeta["result_count"] != tt.wantCount {
+				t.Errorf("result_count = %v, want %d", filterMeta["result_count"], tt.wantCount)
+			}
+
+			tokens := result.Meta["tokens"].(map[string]interface{})
+			if tokens["returned"] != EstimateTokens(result.Content) || tokens["source"] != EstimateTokens(body) {
+				t.Errorf("tokens = %v", tokens)
+			}
+			bytes := result.Meta["bytes"].(map[string]interface{})
+			if bytes["returned"] != len(result.Content) || bytes["source"] != len(body) {
+				t.Errorf("bytes = %v", bytes)
+			}
+		})
+	}
+}
+
+func TestSearchInvalidJSON(t *testing.T) {
+	for _, language := range Languages() {
+		if _, err := Search(language, "not json", "."); err == nil || !strings.Contains(err.Error(), "invalid JSON response") {
+			t.Errorf("%s: expected invalid JSON error, got %v", language, err)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		language   string
+		expression string
+		wantError  string
+	}{
+		{"jmespath", "pets[].name", ""},
+		{"jmespath", "pets[?", "invalid jmespath expression"},
+		{"jsonpath", "$.pets[*].name", ""},
+		{"jsonpath", "$.pets[", "invalid jsonpath expression"},
+		{"jq", ".pets[].name", ""},
+		{"jq", ".pets[", "invalid jq expression"},
+		{"xpath", "//name", "unknown filter language"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language+" "+tt.expression, func(t *testing.T) {
+			err := Compile(tt.language, tt.expression)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("Compile() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("Compile() error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+}
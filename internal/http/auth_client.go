@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
 	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
 	"github.com/rs/zerolog"
 )
@@ -19,13 +20,18 @@ type AuthenticatedHTTPClient struct {
 // NewAuthenticatedHTTPClient creates an HTTP client that applies authentication based on config
 func NewAuthenticatedHTTPClient(config *config.Config, logger zerolog.Logger) *AuthenticatedHTTPClient {
 	// Create lambda-capable client
-	lambdaClient, err := qurlhttp.NewClient()
+	lambdaClient, err := newHTTPClient(config)
 	if err != nil {
 		// Fallback to a basic client that can still do HTTP requests
 		logger.Warn().Err(err).Msg("failed to create lambda-capable client, falling back to basic client")
 		lambdaClient = &qurlhttp.Client{Client: http.DefaultClient}
 	}
 
+	// Invalid options are reported when the executor is created
+	if options, err := lambdaOptions(config); err == nil {
+		lambdaClient.Lambda = options
+	}
+
 	return &AuthenticatedHTTPClient{
 		client: lambdaClient,
 		config: config,
@@ -45,8 +51,13 @@ func (c *AuthenticatedHTTPClient) Do(req *http.Request) (*http.Response, error)
 	// Create a request builder to apply authentication
 	builder := NewRequestBuilder(logger, c.config, nil)
 
+	body, err := peekBody(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeInternal, "failed to read request body for signing")
+	}
+
 	// Apply authentication if configured
-	if err := builder.applyAuthentication(req.Context(), req, req.URL.String()); err != nil {
+	if err := builder.applyAuthentication(req.Context(), req, req.URL.String(), body); err != nil {
 		logger.Error().Err(err).Msg("failed to apply authentication")
 		return nil, err
 	}
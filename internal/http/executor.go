@@ -1,8 +1,11 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -21,6 +24,8 @@ type executor struct {
 	responseHandler ResponseHandler
 	requestBuilder  *RequestBuilder
 	config          *config.Config
+	sleep           func(ctx context.Context, d time.Duration) error // Waits between retries
+	lastEventID     string                                           // Last-Event-ID for event stream reconnects
 }
 
 // NewExecutorWithDependencies creates a new HTTP executor with injected dependencies
@@ -42,6 +47,7 @@ func NewExecutorWithDependencies(
 		responseHandler: responseHandler,
 		requestBuilder:  requestBuilder,
 		config:          config,
+		sleep:           sleepContext,
 	}
 }
 
@@ -55,15 +61,177 @@ func (e *executor) Execute(ctx context.Context, path string) error {
 
 	logger.Debug().Msg("executing HTTP request")
 
+	if e.config.CheckResponse && e.openapi == nil {
+		return errors.New(errors.ErrorTypeConfig, "OpenAPI URL is required for response checking").
+			WithContext("config_type", "openapi").
+			WithContext("suggestion", "set --openapi or QURL_OPENAPI, or drop --check-response")
+	}
+
+	maxSize, err := parseByteSize(e.config.MaxFilesize)
+	if err != nil {
+		return err
+	}
+
 	// Build and execute the request
 	resp, targetURL, err := e.executeRequest(ctx, path)
 	if err != nil {
 		return err
 	}
+	if err := limitResponseBody(resp, maxSize, targetURL); err != nil {
+		return err
+	}
 	defer resp.Body.Close()
 
-	// Handle the response
-	return e.responseHandler.HandleResponse(resp, e.config.PrimaryMethod(), targetURL)
+	if isEventStream(resp) {
+		return e.streamEvents(ctx, path, resp, targetURL, maxSize)
+	}
+
+	if !e.config.CheckResponse {
+		// Handle the response
+		if err := e.responseHandler.HandleResponse(resp, e.config.PrimaryMethod(), targetURL); err != nil {
+			return err
+		}
+		return e.failOnStatus(resp, targetURL)
+	}
+
+	// Buffer the body so it can be both checked and printed
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if timeoutErr := interruptedError(err, targetURL); timeoutErr != nil {
+			return timeoutErr
+		}
+		var qErr *errors.QUrlError
+		if stderrors.As(err, &qErr) {
+			return qErr
+		}
+		return errors.Wrap(err, errors.ErrorTypeNetwork, "failed to read response body").
+			WithContext("url", targetURL)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Print the response first so mismatches are reported after it
+	if err := e.responseHandler.HandleResponse(resp, e.config.PrimaryMethod(), targetURL); err != nil {
+		return err
+	}
+
+	if err := e.checkResponse(ctx, path, resp, body); err != nil {
+		return err
+	}
+	return e.failOnStatus(resp, targetURL)
+}
+
+// streamEvents prints a Server-Sent Events stream. With --sse-reconnect a
+// dropped stream is requested again after the server's retry delay, resuming
+// from the last event ID, until the context is cancelled or the server
+// answers 204 No Content.
+func (e *executor) streamEvents(ctx context.Context, path string, resp *http.Response, targetURL string, maxSize int64) error {
+	method := e.config.PrimaryMethod()
+	state := &EventStreamState{LastEventID: e.config.SSE.LastEventID, Retry: defaultSSERetry}
+
+	for {
+		err := e.responseHandler.HandleEventStream(resp, method, targetURL, state)
+		resp.Body.Close()
+
+		if ctx.Err() != nil {
+			return interruptedError(ctx.Err(), targetURL)
+		}
+		var qErr *errors.QUrlError
+		if stderrors.As(err, &qErr) {
+			return qErr
+		}
+		if !e.config.SSE.Reconnect {
+			if err != nil {
+				return errors.Wrap(err, errors.ErrorTypeNetwork, "event stream failed").
+					WithContext("url", targetURL).
+					WithContext("suggestion", "use --sse-reconnect to resume dropped streams")
+			}
+			return e.failOnStatus(resp, targetURL)
+		}
+
+		event := e.logger.Warn().
+			Str("last_event_id", state.LastEventID).
+			Dur("delay", state.Retry)
+		if err != nil {
+			event = event.Err(err)
+		}
+		event.Msg("event stream ended, reconnecting")
+
+		if err := e.sleep(ctx, state.Retry); err != nil {
+			return errors.Wrap(err, errors.ErrorTypeNetwork, "event stream reconnect cancelled").
+				WithContext("url", targetURL)
+		}
+
+		state.Reconnects++
+		e.lastEventID = state.LastEventID
+		resp, targetURL, err = e.executeRequest(ctx, path)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusNoContent {
+			resp.Body.Close()
+			e.logger.Debug().Msg("server closed the event stream")
+			return nil
+		}
+		if !isEventStream(resp) {
+			resp.Body.Close()
+			return errors.Newf(errors.ErrorTypeNetwork, "reconnect returned %s instead of an event stream", httpStatus(resp)).
+				WithContext("url", targetURL).
+				WithContext("content_type", resp.Header.Get("Content-Type"))
+		}
+		if err := limitResponseBody(resp, maxSize, targetURL); err != nil {
+			return err
+		}
+	}
+}
+
+// failOnStatus returns an HTTP error for 4xx and 5xx responses when --fail is
+// set. The response has already been printed, so only the status is reported.
+func (e *executor) failOnStatus(resp *http.Response, targetURL string) error {
+	if !e.config.Fail || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	return errors.Newf(errors.ErrorTypeHTTP, "server returned %s", httpStatus(resp)).
+		WithContext("status", resp.StatusCode).
+		WithContext("url", targetURL)
+}
+
+// httpStatus formats a response status as "404 Not Found"
+func httpStatus(resp *http.Response) string {
+	if text := http.StatusText(resp.StatusCode); text != "" {
+		return fmt.Sprintf("%d %s", resp.StatusCode, text)
+	}
+	return fmt.Sprintf("%d", resp.StatusCode)
+}
+
+// checkResponse validates a response against the operation's declared responses
+func (e *executor) checkResponse(ctx context.Context, path string, resp *http.Response, body []byte) error {
+	method := e.config.PrimaryMethod()
+	specPath := e.requestBuilder.validationPath(ctx, path)
+
+	match, err := e.openapi.MatchPath(ctx, specPath, method)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeOpenAPI, "failed to load OpenAPI spec for response checking")
+	}
+	if match == nil {
+		return errors.New(errors.ErrorTypeValidation, "no OpenAPI operation matches the request").
+			WithContext("method", method).
+			WithContext("path", specPath)
+	}
+
+	violations := match.ValidateResponse(resp.StatusCode, resp.Header, body)
+	if len(violations) > 0 {
+		return errors.New(errors.ErrorTypeValidation, "response does not match the OpenAPI operation").
+			WithContext("operation", fmt.Sprintf("%s %s", match.Method, match.Path)).
+			WithContext("status", resp.StatusCode).
+			WithContext("violations", violations)
+	}
+
+	e.logger.Debug().
+		Str("operation", fmt.Sprintf("%s %s", match.Method, match.Path)).
+		Msg("response matches OpenAPI operation")
+
+	return nil
 }
 
 // ExecuteForMCP performs an HTTP request and returns the response data
@@ -140,23 +308,74 @@ func (e *executor) executeRequest(ctx context.Context, path string) (*http.Respo
 		return nil, "", errors.Wrap(err, errors.ErrorTypeValidation, "invalid query parameters")
 	}
 
-	// Build HTTP request
-	req, err := e.buildHTTPRequest(ctx, e.config.PrimaryMethod(), targetURL, path)
+	policy, err := newRetryPolicy(e.config.Retry)
 	if err != nil {
-		e.logger.Error().Err(err).Msg("failed to build HTTP request")
 		return nil, "", err
 	}
 
-	// Execute request
-	startTime := time.Now()
-	resp, err := e.httpClient.Do(req)
-	duration := time.Since(startTime)
+	method := e.config.PrimaryMethod()
+	firstAttempt := time.Now()
+
+	for attempt := 0; ; attempt++ {
+		// Build HTTP request. Each attempt gets a fresh request, so the body
+		// is replayed and SigV4 signatures are current.
+		req, err := e.buildHTTPRequest(ctx, method, targetURL, path)
+		if err != nil {
+			e.logger.Error().Err(err).Msg("failed to build HTTP request")
+			return nil, "", err
+		}
+		if e.lastEventID != "" {
+			req.Header.Set("Last-Event-ID", e.lastEventID)
+		}
+
+		// Execute request
+		startTime := time.Now()
+		resp, err := e.httpClient.Do(req)
+		duration := time.Since(startTime)
 
+		if attempt < policy.maxRetries && policy.allows(method) && policy.shouldRetry(resp, err) {
+			wait := policy.delay(attempt, resp, time.Now())
+			if policy.maxTime == 0 || time.Since(firstAttempt)+wait <= policy.maxTime {
+				event := e.logger.Warn().
+					Int("attempt", attempt+1).
+					Int("max_retries", policy.maxRetries).
+					Dur("delay", wait)
+				if err != nil {
+					event = event.Err(err)
+				} else {
+					event = event.Int("status", resp.StatusCode)
+				}
+				event.Msg("retrying request")
+
+				discardBody(resp)
+				if err := e.sleep(ctx, wait); err != nil {
+					if timeoutErr := interruptedError(err, targetURL); timeoutErr != nil {
+						return nil, "", timeoutErr
+					}
+					return nil, "", errors.Wrap(err, errors.ErrorTypeNetwork, "request cancelled while waiting to retry").
+						WithContext("url", targetURL)
+				}
+				continue
+			}
+		}
+
+		return e.requestResult(resp, err, targetURL, duration)
+	}
+}
+
+// requestResult logs the outcome of the final attempt and wraps its error
+func (e *executor) requestResult(resp *http.Response, err error, targetURL string, duration time.Duration) (*http.Response, string, error) {
 	if err != nil {
 		e.logger.Error().
 			Err(err).
 			Dur("duration", duration).
 			Msg("HTTP request failed")
+		if fnErr, ok := lambdaFunctionError(err, e.config.LambdaLogs); ok {
+			return nil, "", fnErr
+		}
+		if timeoutErr := interruptedError(err, targetURL); timeoutErr != nil {
+			return nil, "", timeoutErr.WithContext("duration", duration)
+		}
 		return nil, "", errors.Wrap(err, errors.ErrorTypeNetwork, "HTTP request failed").
 			WithContext("url", targetURL).
 			WithContext("duration", duration)
@@ -170,7 +389,6 @@ func (e *executor) executeRequest(ctx context.Context, path string) (*http.Respo
 	return resp, targetURL, nil
 }
 
-
 // buildHTTPRequest creates an HTTP request with proper headers and body
 func (e *executor) buildHTTPRequest(ctx context.Context, method, targetURL, originalPath string) (*http.Request, error) {
 	return e.requestBuilder.Build(ctx, method, targetURL, originalPath)
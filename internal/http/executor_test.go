@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/pkg/openapi"
 	"github.com/rs/zerolog"
 )
 
@@ -172,6 +174,140 @@ func TestExecutor_ExecuteForMCP(t *testing.T) {
 	}
 }
 
+func TestExecutor_CheckResponse(t *testing.T) {
+	parser := openapi.NewParser()
+	if err := parser.LoadFromBytes([]byte(builderTestSpec)); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	match, err := parser.MatchPath("/pet/1", "PUT")
+	if err != nil || match == nil {
+		t.Fatalf("Failed to match test operation: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		status      int
+		openapi     OpenAPIProvider
+		wantErrType errors.ErrorType
+	}{
+		{
+			name:    "declared status",
+			status:  200,
+			openapi: &mockOpenAPIProvider{match: match},
+		},
+		{
+			name:        "undeclared status",
+			status:      500,
+			openapi:     &mockOpenAPIProvider{match: match},
+			wantErrType: errors.ErrorTypeValidation,
+		},
+		{
+			name:        "no matching operation",
+			status:      200,
+			openapi:     &mockOpenAPIProvider{},
+			wantErrType: errors.ErrorTypeValidation,
+		},
+		{
+			name:        "requires a spec",
+			status:      200,
+			wantErrType: errors.ErrorTypeConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Methods:       []string{"PUT"},
+				CheckResponse: true,
+			}
+			handler := &mockResponseHandler{}
+
+			executor := NewExecutorWithDependencies(
+				zerolog.New(nil),
+				&mockHTTPClient{response: &http.Response{
+					StatusCode: tt.status,
+					Body:       io.NopCloser(strings.NewReader("")),
+					Header:     make(http.Header),
+				}},
+				tt.openapi,
+				&mockURLResolver{url: "https://api.example.com/pet/1"},
+				handler,
+				cfg,
+			)
+
+			err := executor.Execute(context.Background(), "/pet/1")
+			if tt.wantErrType == "" {
+				if err != nil {
+					t.Errorf("Execute() unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.IsType(err, tt.wantErrType) {
+				t.Errorf("Execute() expected %s error, got %v", tt.wantErrType, err)
+			}
+		})
+	}
+}
+
+func TestExecutor_Fail(t *testing.T) {
+	tests := []struct {
+		name       string
+		fail       bool
+		status     int
+		wantErr    bool
+		wantStatus string
+	}{
+		{name: "success with fail", fail: true, status: 200},
+		{name: "redirect with fail", fail: true, status: 304},
+		{name: "client error without fail", status: 404},
+		{name: "server error without fail", status: 500},
+		{name: "client error with fail", fail: true, status: 404, wantErr: true, wantStatus: "404 Not Found"},
+		{name: "server error with fail", fail: true, status: 503, wantErr: true, wantStatus: "503 Service Unavailable"},
+		{name: "unknown status with fail", fail: true, status: 599, wantErr: true, wantStatus: "599"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &mockResponseHandler{}
+			executor := NewExecutorWithDependencies(
+				zerolog.New(nil),
+				&mockHTTPClient{response: &http.Response{
+					StatusCode: tt.status,
+					Body:       io.NopCloser(strings.NewReader(`{"message": "error"}`)),
+					Header:     make(http.Header),
+				}},
+				nil,
+				&mockURLResolver{url: "https://api.example.com/users"},
+				handler,
+				&config.Config{Methods: []string{"GET"}, Fail: tt.fail},
+			)
+
+			err := executor.Execute(context.Background(), "/users")
+			if !handler.called {
+				t.Error("Execute() should print the response before failing")
+			}
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("Execute() unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.IsType(err, errors.ErrorTypeHTTP) {
+				t.Fatalf("Execute() expected http error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantStatus) {
+				t.Errorf("Execute() error %q should contain %q", err.Error(), tt.wantStatus)
+			}
+			if status := errors.GetContext(err)["status"]; status != tt.status {
+				t.Errorf("status context: got %v, expected %d", status, tt.status)
+			}
+			if code := errors.ExitCode(err); code != errors.ExitHTTP {
+				t.Errorf("exit code: got %d, expected %d", code, errors.ExitHTTP)
+			}
+		})
+	}
+}
+
 func TestExecutor_ShowDocs(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -263,6 +399,7 @@ func (m *mockURLResolver) ResolveURL(ctx context.Context, path string) (string,
 
 type mockResponseHandler struct {
 	err error
+	called bool
 	mcpBody string
 	mcpHeaders map[string][]string
 	mcpStatusCode int
@@ -270,6 +407,12 @@ type mockResponseHandler struct {
 }
 
 func (m *mockResponseHandler) HandleResponse(resp *http.Response, method, targetURL string) error {
+	m.called = true
+	return m.err
+}
+
+func (m *mockResponseHandler) HandleEventStream(resp *http.Response, method, targetURL string, state *EventStreamState) error {
+	m.called = true
 	return m.err
 }
 
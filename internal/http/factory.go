@@ -3,7 +3,6 @@ package http
 import (
 	"github.com/brendan.keane/qurl/internal/config"
 	"github.com/brendan.keane/qurl/internal/errors"
-	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
 	"github.com/brendan.keane/qurl/pkg/openapi"
 	"github.com/rs/zerolog"
 )
@@ -25,17 +24,28 @@ func NewClientFactory(logger zerolog.Logger) *ClientFactory {
 // This is the main entry point for all HTTP client creation in the application
 func (f *ClientFactory) CreateExecutor(cfg *config.Config) (HTTPExecutor, error) {
 	// Create the underlying HTTP client (with Lambda support)
-	httpClient, err := qurlhttp.NewClient()
+	httpClient, err := newHTTPClient(cfg)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrorTypeNetwork, "failed to create HTTP client")
 	}
 
+	if httpClient.Lambda, err = lambdaOptions(cfg); err != nil {
+		return nil, err
+	}
+
 	// Create OpenAPI viewer if URL is provided
 	var viewer OpenAPIProvider
 	if cfg.OpenAPIURL != "" {
+		serverVars, err := ParseServerVariables(cfg.ServerVars)
+		if err != nil {
+			return nil, err
+		}
+
 		// Create authenticated HTTP client for OpenAPI spec fetching
 		authClient := NewAuthenticatedHTTPClient(cfg, f.logger)
-		openapiViewer := openapi.NewViewer(authClient, cfg.OpenAPIURL)
+		openapiViewer := openapi.NewViewer(authClient, SpecURL(cfg))
+		openapiViewer.SetServerVariables(serverVars)
+		openapiViewer.SetSpecTimeout(cfg.Timeouts.Spec)
 		viewer = NewOpenAPIAdapter(openapiViewer)
 	}
 
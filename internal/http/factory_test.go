@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/pkg/openapi"
 	"github.com/rs/zerolog"
 )
 
@@ -42,6 +43,22 @@ func TestClientFactory_CreateExecutor(t *testing.T) {
 			},
 			wantErr: false, // Should not error, just won't have server URL
 		},
+		{
+			name: "valid Lambda event format",
+			config: &config.Config{
+				Methods:      []string{"GET"},
+				LambdaFormat: "alb",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid Lambda event format",
+			config: &config.Config{
+				Methods:      []string{"GET"},
+				LambdaFormat: "sqs",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -99,8 +116,10 @@ type mockOpenAPIProvider struct {
 	viewError error
 	baseURL string
 	baseURLError error
-	servers []string
+	servers []openapi.ServerInfo
 	serversError error
+	match *openapi.PathMatch
+	matchError error
 }
 
 func (m *mockOpenAPIProvider) SetHeaders(ctx context.Context, req *http.Request, path, method string) error {
@@ -118,6 +137,10 @@ func (m *mockOpenAPIProvider) BaseURL(ctx context.Context) (string, error) {
 	return m.baseURL, m.baseURLError
 }
 
-func (m *mockOpenAPIProvider) GetServers() ([]string, error) {
+func (m *mockOpenAPIProvider) GetServers(ctx context.Context) ([]openapi.ServerInfo, error) {
 	return m.servers, m.serversError
+}
+
+func (m *mockOpenAPIProvider) MatchPath(ctx context.Context, path, method string) (*openapi.PathMatch, error) {
+	return m.match, m.matchError
 }
\ No newline at end of file
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+)
+
+// formField is one parsed -F argument
+type formField struct {
+	name        string
+	value       string // Text value, or the file path for file fields
+	file        bool   // name=@path: upload the file as a part with a filename
+	contents    bool   // name=<path: use the file contents as a text value
+	contentType string // From ;type=
+	filename    string // From ;filename=, defaults to the file's base name
+}
+
+// parseFormField parses curl-style -F arguments:
+// name=value, name=@path[;type=mime][;filename=name] and name=<path
+func parseFormField(arg string) (formField, error) {
+	name, value, ok := strings.Cut(arg, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return formField{}, errors.New(errors.ErrorTypeValidation, "invalid form field").
+			WithContext("field", arg).
+			WithContext("suggestion", "use name=value, name=@file or name=<file")
+	}
+
+	field := formField{name: name, value: value}
+
+	switch {
+	case strings.HasPrefix(value, "@"):
+		field.file = true
+	case strings.HasPrefix(value, "<"):
+		field.contents = true
+	default:
+		return field, nil
+	}
+
+	options := strings.Split(value[1:], ";")
+	field.value = options[0]
+	for _, option := range options[1:] {
+		key, val, _ := strings.Cut(option, "=")
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "type":
+			field.contentType = strings.TrimSpace(val)
+		case "filename":
+			field.filename = strings.Trim(strings.TrimSpace(val), `"`)
+		default:
+			return formField{}, errors.New(errors.ErrorTypeValidation, "invalid form field option").
+				WithContext("field", arg).
+				WithContext("option", option).
+				WithContext("suggestion", "supported options are ;type= and ;filename=")
+		}
+	}
+
+	if field.value == "" {
+		return formField{}, errors.New(errors.ErrorTypeValidation, "form field is missing a file path").
+			WithContext("field", arg)
+	}
+
+	return field, nil
+}
+
+// buildMultipartBody encodes -F fields as a multipart/form-data body and returns
+// it with its Content-Type, which carries the boundary
+func buildMultipartBody(args []string, stdin io.Reader) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, arg := range args {
+		field, err := parseFormField(arg)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !field.file && !field.contents {
+			if err := writer.WriteField(field.name, field.value); err != nil {
+				return nil, "", errors.Wrap(err, errors.ErrorTypeInternal, "failed to encode form field")
+			}
+			continue
+		}
+
+		data, err := readFormFile(field.value, stdin)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if field.contents {
+			if err := writer.WriteField(field.name, string(data)); err != nil {
+				return nil, "", errors.Wrap(err, errors.ErrorTypeInternal, "failed to encode form field")
+			}
+			continue
+		}
+
+		filename := field.filename
+		if filename == "" && field.value != "-" {
+			filename = filepath.Base(field.value)
+		}
+		if filename == "" {
+			filename = "-"
+		}
+
+		contentType := field.contentType
+		if contentType == "" {
+			contentType = formFileContentType(filename, data)
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(field.name), escapeQuotes(filename)))
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", errors.Wrap(err, errors.ErrorTypeInternal, "failed to encode form file")
+		}
+		if _, err := part.Write(data); err != nil {
+			return nil, "", errors.Wrap(err, errors.ErrorTypeInternal, "failed to encode form file")
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", errors.Wrap(err, errors.ErrorTypeInternal, "failed to encode form body")
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// readFormFile reads a form file, with - meaning stdin
+func readFormFile(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrorTypeValidation, "failed to read form file from stdin")
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrorTypeValidation, "failed to read form file").
+			WithContext("path", path)
+	}
+	return data, nil
+}
+
+// formFileContentType guesses a file part's content type from its extension,
+// falling back to sniffing the contents
+func formFileContentType(filename string, data []byte) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(filename)); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(data)
+}
+
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// escapeQuotes escapes a Content-Disposition parameter, as mime/multipart does
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
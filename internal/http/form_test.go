@@ -0,0 +1,222 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/pkg/openapi"
+	"github.com/rs/zerolog"
+)
+
+func TestParseFormField(t *testing.T) {
+	tests := []struct {
+		name        string
+		arg         string
+		expected    formField
+		expectError bool
+	}{
+		{
+			name:     "text field",
+			arg:      "name=doggie",
+			expected: formField{name: "name", value: "doggie"},
+		},
+		{
+			name:     "text field keeps semicolons",
+			arg:      "note=a;b",
+			expected: formField{name: "note", value: "a;b"},
+		},
+		{
+			name:     "file field",
+			arg:      "photo=@dog.png",
+			expected: formField{name: "photo", value: "dog.png", file: true},
+		},
+		{
+			name:     "file field with type and filename",
+			arg:      `photo=@/tmp/upload;type=image/jpeg;filename="dog.jpg"`,
+			expected: formField{name: "photo", value: "/tmp/upload", file: true, contentType: "image/jpeg", filename: "dog.jpg"},
+		},
+		{
+			name:     "text from file contents",
+			arg:      "bio=<bio.txt",
+			expected: formField{name: "bio", value: "bio.txt", contents: true},
+		},
+		{
+			name:        "missing equals",
+			arg:         "photo",
+			expectError: true,
+		},
+		{
+			name:        "missing file path",
+			arg:         "photo=@",
+			expectError: true,
+		},
+		{
+			name:        "unknown option",
+			arg:         "photo=@dog.png;encoder=base64",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := parseFormField(tt.arg)
+
+			if tt.expectError {
+				if !errors.IsType(err, errors.ErrorTypeValidation) {
+					t.Errorf("Expected validation error, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if field != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, field)
+			}
+		})
+	}
+}
+
+func TestRequestBuilder_Form(t *testing.T) {
+	dir := t.TempDir()
+	photo := filepath.Join(dir, "dog.png")
+	if err := os.WriteFile(photo, []byte("\x89PNG\r\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write form file: %v", err)
+	}
+	bio := filepath.Join(dir, "bio.txt")
+	if err := os.WriteFile(bio, []byte("good dog"), 0o644); err != nil {
+		t.Fatalf("Failed to write form file: %v", err)
+	}
+
+	cfg := &config.Config{Form: []string{
+		"name=doggie",
+		"photo=@" + photo,
+		"bio=<" + bio,
+		"doc=@-;type=text/markdown;filename=README.md",
+	}}
+	builder := NewRequestBuilder(zerolog.Nop(), cfg, nil)
+	builder.stdin = strings.NewReader("# Doggie")
+
+	req, err := builder.Build(context.Background(), "POST", "https://api.example.com/pet", "/pet")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("Expected multipart/form-data content type, got %q", req.Header.Get("Content-Type"))
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+	type part struct{ filename, contentType, content string }
+	parts := make(map[string]part)
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Invalid multipart body: %v", err)
+		}
+		content, _ := io.ReadAll(p)
+		parts[p.FormName()] = part{p.FileName(), p.Header.Get("Content-Type"), string(content)}
+	}
+
+	expected := map[string]part{
+		"name":  {"", "", "doggie"},
+		"photo": {"dog.png", "image/png", "\x89PNG\r\n"},
+		"bio":   {"", "", "good dog"},
+		"doc":   {"README.md", "text/markdown", "# Doggie"},
+	}
+	for name, want := range expected {
+		if got := parts[name]; got != want {
+			t.Errorf("Part %q = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestRequestBuilder_FormConflictsWithData(t *testing.T) {
+	cfg := &config.Config{Data: "name=doggie", Form: []string{"name=doggie"}}
+	builder := NewRequestBuilder(zerolog.Nop(), cfg, nil)
+
+	_, err := builder.Build(context.Background(), "POST", "https://api.example.com/pet", "/pet")
+	if !errors.IsType(err, errors.ErrorTypeValidation) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestRequestBuilder_FormCheckedAgainstSpec(t *testing.T) {
+	spec := `{
+		"openapi": "3.0.0",
+		"info": {"title": "Form API", "version": "1.0.0"},
+		"paths": {
+			"/pet": {
+				"post": {
+					"requestBody": {
+						"content": {
+							"multipart/form-data": {
+								"schema": {
+									"type": "object",
+									"required": ["name"],
+									"properties": {"name": {"type": "string"}, "age": {"type": "integer"}}
+								}
+							}
+						}
+					},
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		}
+	}`
+	parser := openapi.NewParser()
+	if err := parser.LoadFromBytes([]byte(spec)); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	match, err := parser.MatchPath("/pet", "POST")
+	if err != nil || match == nil {
+		t.Fatalf("Failed to match test operation: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		form     []string
+		provider OpenAPIProvider
+		wantErr  bool
+	}{
+		{"matching form", []string{"name=doggie", "age=3"}, &mockOpenAPIProvider{match: match}, false},
+		{"misspelled field", []string{"nmae=doggie"}, &mockOpenAPIProvider{match: match}, true},
+		{"field type violation", []string{"name=doggie", "age=old"}, &mockOpenAPIProvider{match: match}, true},
+		{"no matching operation", []string{"nmae=doggie"}, &mockOpenAPIProvider{}, false},
+		{"no spec", []string{"nmae=doggie"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Form: tt.form}
+			builder := NewRequestBuilder(zerolog.Nop(), cfg, tt.provider)
+
+			_, err := builder.Build(context.Background(), "POST", "https://api.example.com/pet", "/pet")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.IsType(err, errors.ErrorTypeValidation) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+}
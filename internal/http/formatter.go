@@ -0,0 +1,368 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/brendan.keane/qurl/pkg/openapi"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxFormattedBody is the largest body that is buffered for pretty-printing.
+// Larger bodies are written as they arrive, unformatted.
+const maxFormattedBody = 8 << 20
+
+// Styles for formatted bodies, in the palette of the openapi Displayer
+var (
+	keyStyle     = lipgloss.NewStyle().Foreground(openapi.ColorBlue)
+	stringStyle  = lipgloss.NewStyle().Foreground(openapi.ColorGreen)
+	numberStyle  = lipgloss.NewStyle().Foreground(openapi.ColorYellow)
+	literalStyle = lipgloss.NewStyle().Foreground(openapi.ColorPurple)
+	punctStyle   = lipgloss.NewStyle().Foreground(openapi.ColorForeground)
+	commentStyle = lipgloss.NewStyle().Foreground(openapi.ColorComment).Italic(true)
+	tagStyle     = lipgloss.NewStyle().Foreground(openapi.ColorRed)
+)
+
+// bodyFormat is a response body syntax that can be pretty-printed
+type bodyFormat int
+
+const (
+	formatNone bodyFormat = iota
+	formatJSON
+	formatYAML
+	formatXML
+)
+
+// responseFormatter indents and colourises JSON, YAML and XML bodies for a terminal
+type responseFormatter struct {
+	color bool
+}
+
+// newResponseFormatter returns the formatter for output to stdout, or nil when
+// the body should be written unchanged: with --raw, with -o, or when stdout is
+// not a terminal. NO_COLOR keeps the indentation but drops the colours.
+func newResponseFormatter(raw bool, output string) *responseFormatter {
+	if raw || (output != "" && output != "-") || !isTerminal(os.Stdout) {
+		return nil
+	}
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return &responseFormatter{color: !noColor}
+}
+
+// detectFormat selects the syntax from the Content-Type. A body without a
+// Content-Type that parses as JSON is treated as JSON.
+func detectFormat(contentType string, body []byte) bodyFormat {
+	if contentType == "" {
+		if json.Valid(body) {
+			return formatJSON
+		}
+		return formatNone
+	}
+	return mediaFormat(contentType)
+}
+
+// mediaFormat selects the syntax from a Content-Type alone
+func mediaFormat(contentType string) bodyFormat {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return formatNone
+	}
+
+	switch {
+	case mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json"):
+		return formatJSON
+	case mediaType == "application/yaml" || mediaType == "application/x-yaml" || mediaType == "text/yaml" ||
+		mediaType == "text/x-yaml" || strings.HasSuffix(mediaType, "+yaml"):
+		return formatYAML
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return formatXML
+	}
+	return formatNone
+}
+
+// formatBody returns a reader for the formatted body. Only JSON, YAML and XML
+// are buffered; other bodies are returned as they are so they keep streaming.
+// Bodies that are too large or that fail to parse are passed through unchanged.
+func (f *responseFormatter) formatBody(contentType string, body io.Reader) (io.Reader, error) {
+	if contentType == "" {
+		// Without a Content-Type only a body that starts like JSON is buffered
+		peeked := bufio.NewReader(body)
+		start, err := peeked.Peek(1)
+		if err != nil || (start[0] != '{' && start[0] != '[') {
+			return peeked, nil
+		}
+		body = peeked
+	} else if mediaFormat(contentType) == formatNone {
+		return body, nil
+	}
+
+	buffered, err := io.ReadAll(io.LimitReader(body, maxFormattedBody+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(buffered) > maxFormattedBody {
+		return io.MultiReader(bytes.NewReader(buffered), body), nil
+	}
+
+	formatted, ok := f.format(detectFormat(contentType, buffered), buffered)
+	if !ok {
+		return bytes.NewReader(buffered), nil
+	}
+	return strings.NewReader(formatted), nil
+}
+
+// format pretty-prints body, reporting false when it cannot be parsed
+func (f *responseFormatter) format(format bodyFormat, body []byte) (string, bool) {
+	var out strings.Builder
+	var err error
+
+	switch format {
+	case formatJSON:
+		err = f.writeJSON(&out, body)
+	case formatYAML:
+		f.writeYAML(&out, body)
+	case formatXML:
+		err = f.writeXML(&out, body)
+	default:
+		return "", false
+	}
+	if err != nil {
+		return "", false
+	}
+
+	if !strings.HasSuffix(out.String(), "\n") {
+		out.WriteByte('\n')
+	}
+	return out.String(), true
+}
+
+// paint renders text in style when colour is enabled
+func (f *responseFormatter) paint(style lipgloss.Style, text string) string {
+	if !f.color {
+		return text
+	}
+	return style.Render(text)
+}
+
+// writeJSON indents JSON with two spaces, keeping the key order and number
+// literals of the original. Several top-level values, as in NDJSON, are
+// written one after another.
+func (f *responseFormatter) writeJSON(out *strings.Builder, body []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	type container struct {
+		object bool
+		count  int // Keys and values written so far
+	}
+	var stack []container
+
+	newline := func() {
+		out.WriteByte('\n')
+		out.WriteString(strings.Repeat("  ", len(stack)))
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+			closed := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if closed.count > 0 {
+				newline()
+			}
+			out.WriteString(f.paint(punctStyle, delim.String()))
+			continue
+		}
+
+		isKey := false
+		if len(stack) == 0 {
+			if out.Len() > 0 {
+				out.WriteByte('\n')
+			}
+		} else {
+			top := &stack[len(stack)-1]
+			isKey = top.object && top.count%2 == 0
+			switch {
+			case isKey || !top.object:
+				if top.count > 0 {
+					out.WriteString(f.paint(punctStyle, ","))
+				}
+				newline()
+			default:
+				out.WriteString(f.paint(punctStyle, ":") + " ")
+			}
+			top.count++
+		}
+
+		switch value := tok.(type) {
+		case json.Delim:
+			out.WriteString(f.paint(punctStyle, value.String()))
+			stack = append(stack, container{object: value == '{'})
+		case string:
+			quoted, err := quoteJSON(value)
+			if err != nil {
+				return err
+			}
+			if isKey {
+				out.WriteString(f.paint(keyStyle, quoted))
+			} else {
+				out.WriteString(f.paint(stringStyle, quoted))
+			}
+		case json.Number:
+			out.WriteString(f.paint(numberStyle, value.String()))
+		case bool:
+			if value {
+				out.WriteString(f.paint(literalStyle, "true"))
+			} else {
+				out.WriteString(f.paint(literalStyle, "false"))
+			}
+		case nil:
+			out.WriteString(f.paint(literalStyle, "null"))
+		}
+	}
+
+	if len(stack) != 0 {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// quoteJSON encodes s as a JSON string without escaping <, > and &
+func quoteJSON(s string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// yamlKeyPattern matches the indentation, optional list marker and key of a mapping line
+var yamlKeyPattern = regexp.MustCompile(`^(\s*(?:-\s+)?)([^\s#:'"][^#:]*?|"[^"]*"|'[^']*'):(\s|$)`)
+
+// writeYAML colourises keys and comments. YAML is already indented, so the
+// text is otherwise unchanged.
+func (f *responseFormatter) writeYAML(out *strings.Builder, body []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, maxFormattedBody)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			out.WriteString(f.paint(commentStyle, line))
+		case yamlKeyPattern.MatchString(line):
+			m := yamlKeyPattern.FindStringSubmatchIndex(line)
+			out.WriteString(line[:m[4]])
+			out.WriteString(f.paint(keyStyle, line[m[4]:m[5]]))
+			out.WriteString(f.paint(punctStyle, ":"))
+			out.WriteString(line[m[5]+1:])
+		default:
+			out.WriteString(line)
+		}
+		out.WriteByte('\n')
+	}
+}
+
+// writeXML indents elements with two spaces. Text-only elements stay on one
+// line; namespace prefixes are kept as written.
+func (f *responseFormatter) writeXML(out *strings.Builder, body []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+
+	depth := 0
+	// open is true after a start tag whose content has not started yet;
+	// inline is true after text, so the end tag follows on the same line
+	open, inline := false, false
+
+	newline := func() {
+		if out.Len() > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString(strings.Repeat("  ", depth))
+	}
+
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			newline()
+			out.WriteString(f.paint(punctStyle, "<") + f.paint(tagStyle, xmlName(t.Name)))
+			for _, attr := range t.Attr {
+				var value bytes.Buffer
+				xml.EscapeText(&value, []byte(attr.Value))
+				out.WriteString(" " + f.paint(keyStyle, xmlName(attr.Name)) + f.paint(punctStyle, "=") +
+					f.paint(stringStyle, `"`+value.String()+`"`))
+			}
+			out.WriteString(f.paint(punctStyle, ">"))
+			depth++
+			open, inline = true, false
+		case xml.EndElement:
+			depth--
+			if !open && !inline {
+				newline()
+			}
+			out.WriteString(f.paint(punctStyle, "</") + f.paint(tagStyle, xmlName(t.Name)) + f.paint(punctStyle, ">"))
+			open, inline = false, false
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" {
+				continue
+			}
+			if !open {
+				newline()
+			}
+			var escaped bytes.Buffer
+			xml.EscapeText(&escaped, []byte(text))
+			out.WriteString(escaped.String())
+			open, inline = false, true
+		case xml.Comment:
+			newline()
+			out.WriteString(f.paint(commentStyle, "<!--"+string(t)+"-->"))
+			open, inline = false, false
+		case xml.ProcInst:
+			newline()
+			out.WriteString(f.paint(commentStyle, "<?"+t.Target+" "+string(t.Inst)+"?>"))
+			open, inline = false, false
+		case xml.Directive:
+			newline()
+			out.WriteString(f.paint(commentStyle, "<!"+string(t)+">"))
+			open, inline = false, false
+		}
+	}
+
+	if depth != 0 {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// xmlName formats a raw token name with its namespace prefix
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        bodyFormat
+	}{
+		{"application/json", `{}`, formatJSON},
+		{"application/json; charset=utf-8", `{}`, formatJSON},
+		{"application/problem+json", `{}`, formatJSON},
+		{"application/yaml", "a: 1", formatYAML},
+		{"text/x-yaml", "a: 1", formatYAML},
+		{"application/xml", "<a/>", formatXML},
+		{"application/atom+xml", "<feed/>", formatXML},
+		{"text/plain", `{}`, formatNone},
+		{"", `{"sniffed": true}`, formatJSON},
+		{"", "plain text", formatNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType+" "+tt.body, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectFormat(tt.contentType, []byte(tt.body)))
+		})
+	}
+}
+
+func TestResponseFormatterFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format bodyFormat
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "minified JSON keeps key order and numbers",
+			format: formatJSON,
+			body:   `{"zeta":1.50,"alpha":[1,{"b":null,"a":true}],"empty":{},"list":[],"html":"<b>&</b>"}`,
+			want: `{
+  "zeta": 1.50,
+  "alpha": [
+    1,
+    {
+      "b": null,
+      "a": true
+    }
+  ],
+  "empty": {},
+  "list": [],
+  "html": "<b>&</b>"
+}
+`,
+			wantOK: true,
+		},
+		{
+			name:   "NDJSON values",
+			format: formatJSON,
+			body:   "{\"n\":1}\n{\"n\":2}\n",
+			want:   "{\n  \"n\": 1\n}\n{\n  \"n\": 2\n}\n",
+			wantOK: true,
+		},
+		{
+			name:   "top-level scalar",
+			format: formatJSON,
+			body:   `"ok"`,
+			want:   "\"ok\"\n",
+			wantOK: true,
+		},
+		{
+			name:   "invalid JSON",
+			format: formatJSON,
+			body:   `{"broken":`,
+		},
+		{
+			name:   "YAML is unchanged without colour",
+			format: formatYAML,
+			body:   "# pets\nname: doggie\ntags:\n  - name: good\n",
+			want:   "# pets\nname: doggie\ntags:\n  - name: good\n",
+			wantOK: true,
+		},
+		{
+			name:   "XML",
+			format: formatXML,
+			body:   `<?xml version="1.0"?><pet id="1"><name>doggie</name><tags><tag/></tags><!-- note --></pet>`,
+			want: `<?xml version="1.0"?>
+<pet id="1">
+  <name>doggie</name>
+  <tags>
+    <tag></tag>
+  </tags>
+  <!-- note -->
+</pet>
+`,
+			wantOK: true,
+		},
+		{
+			name:   "XML with namespace prefixes",
+			format: formatXML,
+			body:   `<s:Envelope xmlns:s="urn:soap"><s:Body>x &amp; y</s:Body></s:Envelope>`,
+			want:   "<s:Envelope xmlns:s=\"urn:soap\">\n  <s:Body>x &amp; y</s:Body>\n</s:Envelope>\n",
+			wantOK: true,
+		},
+		{
+			name:   "unclosed XML",
+			format: formatXML,
+			body:   `<pet><name>doggie</name>`,
+		},
+		{
+			name:   "other formats",
+			format: formatNone,
+			body:   "plain text",
+		},
+	}
+
+	formatter := &responseFormatter{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := formatter.format(tt.format, []byte(tt.body))
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResponseFormatterFormatBody(t *testing.T) {
+	formatter := &responseFormatter{}
+
+	reader, err := formatter.formatBody("application/json", strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	formatted, _ := io.ReadAll(reader)
+	assert.Equal(t, "{\n  \"a\": 1\n}\n", string(formatted))
+
+	// Bodies that do not parse are written unchanged
+	reader, err = formatter.formatBody("application/json", strings.NewReader(`not json`))
+	require.NoError(t, err)
+	unchanged, _ := io.ReadAll(reader)
+	assert.Equal(t, "not json", string(unchanged))
+}
+
+func TestResponseFormatterStreamsOtherTypes(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "first\n")
+		w.(http.Flusher).Flush()
+		<-release
+		fmt.Fprint(w, "second\n")
+	}))
+	defer server.Close()
+	defer close(release)
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The first chunk is readable while the server is still sending
+	formatter := &responseFormatter{color: true}
+	line := make(chan string, 1)
+	go func() {
+		reader, err := formatter.formatBody(resp.Header.Get("Content-Type"), resp.Body)
+		if err != nil {
+			line <- err.Error()
+			return
+		}
+		first, _ := bufio.NewReader(reader).ReadString('\n')
+		line <- first
+	}()
+	select {
+	case first := <-line:
+		assert.Equal(t, "first\n", first)
+	case <-time.After(2 * time.Second):
+		t.Fatal("text/plain body was buffered instead of streamed")
+	}
+}
+
+func TestNewResponseFormatter(t *testing.T) {
+	// Raw output and output files are never formatted; tests do not run on a
+	// terminal, so stdout is not formatted either
+	assert.Nil(t, newResponseFormatter(true, ""))
+	assert.Nil(t, newResponseFormatter(false, "out.json"))
+	assert.Nil(t, newResponseFormatter(false, ""))
+}
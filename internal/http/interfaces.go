@@ -3,6 +3,8 @@ package http
 import (
 	"context"
 	"net/http"
+
+	"github.com/brendan.keane/qurl/pkg/openapi"
 )
 
 // HTTPExecutor defines the core HTTP execution interface
@@ -29,6 +31,7 @@ type URLResolver interface {
 type ResponseHandler interface {
 	HandleResponse(resp *http.Response, method, targetURL string) error
 	HandleResponseForMCP(resp *http.Response, method, targetURL string) (string, map[string][]string, int, error)
+	HandleEventStream(resp *http.Response, method, targetURL string, state *EventStreamState) error
 }
 
 // HTTPClientProvider defines interface for the underlying HTTP client
@@ -43,5 +46,6 @@ type OpenAPIProvider interface {
 	SetHeaders(ctx context.Context, req *http.Request, path, method string) error
 	View(ctx context.Context, path, method string) (string, error)
 	BaseURL(ctx context.Context) (string, error)
-	GetServers() ([]string, error)
+	GetServers(ctx context.Context) ([]openapi.ServerInfo, error)
+	MatchPath(ctx context.Context, path, method string) (*openapi.PathMatch, error)
 }
\ No newline at end of file
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/pkg/openapi"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAPIFromLocalLambdaEndpoint fetches a spec from a function behind a
+// local Invoke API, as served by sam local start-lambda
+func TestOpenAPIFromLocalLambdaEndpoint(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	var invoked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		invoked = append(invoked, r.URL.Path)
+
+		var event events.APIGatewayV2HTTPRequest
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		spec := `{"openapi": "3.0.0", "info": {"title": "Local", "version": "1.0.0"},
+			"servers": [{"url": "lambda://api-function"}], "paths": {}}`
+		json.NewEncoder(w).Encode(events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       spec,
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		OpenAPIURL:     "lambda://api-function/openapi.json",
+		LambdaEndpoint: server.URL,
+	}
+
+	viewer := openapi.NewViewer(NewAuthenticatedHTTPClient(cfg, zerolog.Nop()), cfg.OpenAPIURL)
+	baseURL, err := viewer.BaseURL(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "lambda://api-function", baseURL)
+	assert.Equal(t, []string{"/2015-03-31/functions/api-function/invocations"}, invoked)
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
+)
+
+// lambdaOptions converts the Lambda settings in the config to client options
+func lambdaOptions(cfg *config.Config) (qurlhttp.LambdaOptions, error) {
+	format, err := qurlhttp.ParseLambdaFormat(cfg.LambdaFormat)
+	if err != nil {
+		return qurlhttp.LambdaOptions{}, errors.Wrap(err, errors.ErrorTypeValidation, "invalid Lambda event format").
+			WithContext("format", cfg.LambdaFormat).
+			WithContext("valid_formats", qurlhttp.LambdaFormats)
+	}
+
+	invocationType, err := qurlhttp.ParseLambdaInvocationType(cfg.LambdaInvocationType)
+	if err != nil {
+		return qurlhttp.LambdaOptions{}, errors.Wrap(err, errors.ErrorTypeValidation, "invalid Lambda invocation type").
+			WithContext("invocation_type", cfg.LambdaInvocationType).
+			WithContext("valid_types", qurlhttp.LambdaInvocationTypes)
+	}
+
+	if cfg.LambdaStream && invocationType != types.InvocationTypeRequestResponse {
+		return qurlhttp.LambdaOptions{}, errors.New(errors.ErrorTypeValidation, "response streaming requires the RequestResponse invocation type").
+			WithContext("invocation_type", invocationType).
+			WithContext("suggestion", "drop --lambda-stream or --lambda-invocation-type")
+	}
+
+	event, err := lambdaEventConfig(cfg.LambdaContext)
+	if err != nil {
+		return qurlhttp.LambdaOptions{}, err
+	}
+
+	if cfg.LambdaEndpoint != "" {
+		if u, err := url.Parse(cfg.LambdaEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return qurlhttp.LambdaOptions{}, errors.New(errors.ErrorTypeValidation, "invalid Lambda endpoint").
+				WithContext("endpoint", cfg.LambdaEndpoint).
+				WithContext("suggestion", "use an http or https URL (e.g., --lambda-endpoint http://localhost:3001)")
+		}
+	}
+
+	options := qurlhttp.LambdaOptions{
+		Format:         format,
+		InvocationType: invocationType,
+		Stream:         cfg.LambdaStream,
+		Endpoint:       cfg.LambdaEndpoint,
+		Event:          event,
+	}
+	if cfg.LambdaLogs {
+		options.LogWriter = os.Stderr
+	}
+
+	return options, nil
+}
+
+// lambdaEventConfig converts the --lambda-* request context settings to the
+// event config. Unset fields are left empty for the client's defaults.
+func lambdaEventConfig(lc config.LambdaContextConfig) (qurlhttp.LambdaConfig, error) {
+	event := qurlhttp.LambdaConfig{
+		Stage:    lc.Stage,
+		SourceIP: lc.SourceIP,
+	}
+
+	if lc.SourceIP != "" && net.ParseIP(lc.SourceIP) == nil {
+		return event, errors.New(errors.ErrorTypeValidation, "invalid Lambda source IP").
+			WithContext("source_ip", lc.SourceIP)
+	}
+
+	if lc.IAMUserARN != "" {
+		if parts := strings.Split(lc.IAMUserARN, ":"); len(parts) < 6 || parts[0] != "arn" || parts[4] == "" {
+			return event, errors.New(errors.ErrorTypeValidation, "invalid IAM caller ARN").
+				WithContext("iam_arn", lc.IAMUserARN).
+				WithContext("suggestion", "use an ARN with an account ID (e.g., --lambda-iam-arn arn:aws:iam::123456789012:user/alice)")
+		}
+		event.Authorizer.IAMUserARN = lc.IAMUserARN
+	}
+
+	var err error
+	if event.StageVariables, err = parseLambdaPairs("lambda-stage-var", lc.StageVars); err != nil {
+		return event, err
+	}
+	if event.PathParameters, err = parseLambdaPairs("lambda-path-param", lc.PathParams); err != nil {
+		return event, err
+	}
+	if event.Authorizer.JWTClaims, err = parseLambdaPairs("lambda-claim", lc.Claims); err != nil {
+		return event, err
+	}
+
+	authorizer, err := parseLambdaPairs("lambda-authorizer", lc.Authorizer)
+	if err != nil {
+		return event, err
+	}
+	if len(authorizer) > 0 {
+		event.Authorizer.Lambda = make(map[string]interface{}, len(authorizer))
+		for key, value := range authorizer {
+			event.Authorizer.Lambda[key] = value
+		}
+	}
+
+	event.Authorizer.JWTScopes = lc.Scopes
+
+	return event, nil
+}
+
+// parseLambdaPairs parses name=value pairs from a --lambda-* flag. Later
+// pairs override earlier ones, so flags override profile values.
+func parseLambdaPairs(flag string, pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, errors.New(errors.ErrorTypeValidation, "invalid name=value pair").
+				WithContext("flag", "--"+flag).
+				WithContext("value", pair).
+				WithContext("suggestion", fmt.Sprintf("use the format name=value (e.g., --%s name=value)", flag))
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+	return values, nil
+}
+
+// lambdaFunctionError converts a failed Lambda invocation into a QUrlError that
+// carries the function's error type, message and stack trace
+func lambdaFunctionError(err error, logsShown bool) (*errors.QUrlError, bool) {
+	var fnErr *qurlhttp.LambdaFunctionError
+	if !stderrors.As(err, &fnErr) {
+		return nil, false
+	}
+
+	qErr := errors.New(errors.ErrorTypeLambda, fnErr.Error()).
+		WithContext("function", fnErr.Target.String()).
+		WithContext("function_error", fnErr.Kind)
+	if fnErr.Type != "" {
+		qErr.WithContext("error_type", fnErr.Type)
+	}
+	if fnErr.Message != "" {
+		qErr.WithContext("error_message", fnErr.Message)
+	}
+	if len(fnErr.StackTrace) > 0 {
+		qErr.WithContext("stack_trace", fnErr.StackTrace)
+	}
+	if !logsShown {
+		qErr.WithContext("suggestion", "use --lambda-logs to see the function's log output")
+	}
+
+	return qErr, true
+}
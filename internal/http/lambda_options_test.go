@@ -0,0 +1,170 @@
+package http
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
+)
+
+func TestLambdaOptions(t *testing.T) {
+	options, err := lambdaOptions(&config.Config{LambdaFormat: "v1", LambdaLogs: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if options.Format != qurlhttp.LambdaFormatV1 {
+		t.Errorf("Expected format v1, got %q", options.Format)
+	}
+	if options.LogWriter == nil {
+		t.Error("Expected a log writer with --lambda-logs")
+	}
+
+	if _, err := lambdaOptions(&config.Config{LambdaFormat: "sqs"}); !errors.IsType(err, errors.ErrorTypeValidation) {
+		t.Errorf("Expected validation error for unknown format, got %v", err)
+	}
+
+	options, err = lambdaOptions(&config.Config{LambdaEndpoint: "http://localhost:3001"})
+	if err != nil || options.Endpoint != "http://localhost:3001" {
+		t.Errorf("Expected endpoint http://localhost:3001, got %q (err %v)", options.Endpoint, err)
+	}
+
+	options, err = lambdaOptions(&config.Config{LambdaInvocationType: "event"})
+	if err != nil || options.InvocationType != types.InvocationTypeEvent {
+		t.Errorf("Expected Event invocation, got %q (err %v)", options.InvocationType, err)
+	}
+	if _, err := lambdaOptions(&config.Config{LambdaInvocationType: "stream"}); !errors.IsType(err, errors.ErrorTypeValidation) {
+		t.Errorf("Expected validation error for unknown invocation type, got %v", err)
+	}
+
+	options, err = lambdaOptions(&config.Config{LambdaStream: true})
+	if err != nil || !options.Stream {
+		t.Errorf("Expected streaming to be enabled, got %v (err %v)", options.Stream, err)
+	}
+	if _, err := lambdaOptions(&config.Config{LambdaStream: true, LambdaInvocationType: "Event"}); !errors.IsType(err, errors.ErrorTypeValidation) {
+		t.Errorf("Expected validation error for streaming an Event invocation, got %v", err)
+	}
+
+	for _, endpoint := range []string{"localhost:3001", "ftp://localhost", "http://"} {
+		if _, err := lambdaOptions(&config.Config{LambdaEndpoint: endpoint}); !errors.IsType(err, errors.ErrorTypeValidation) {
+			t.Errorf("Expected validation error for endpoint %q, got %v", endpoint, err)
+		}
+	}
+}
+
+func TestLambdaEventConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		context  config.LambdaContextConfig
+		wantErr  bool
+		expected func(t *testing.T, event qurlhttp.LambdaConfig)
+	}{
+		{
+			name: "empty context leaves defaults to the client",
+			expected: func(t *testing.T, event qurlhttp.LambdaConfig) {
+				if event.Stage != "" || event.StageVariables != nil || !event.Authorizer.IsZero() {
+					t.Errorf("Expected an empty event config, got %+v", event)
+				}
+			},
+		},
+		{
+			name: "all settings",
+			context: config.LambdaContextConfig{
+				Stage:      "prod",
+				SourceIP:   "203.0.113.7",
+				StageVars:  []string{"table=users"},
+				PathParams: []string{"id=42"},
+				Claims:     []string{"sub=user-1", "groups=admin,ops", "sub=user-2"},
+				Scopes:     []string{"users/read"},
+				Authorizer: []string{"tenant=acme"},
+				IAMUserARN: "arn:aws:iam::210987654321:user/alice",
+			},
+			expected: func(t *testing.T, event qurlhttp.LambdaConfig) {
+				if event.Stage != "prod" || event.SourceIP != "203.0.113.7" {
+					t.Errorf("Stage = %q, SourceIP = %q", event.Stage, event.SourceIP)
+				}
+				if event.StageVariables["table"] != "users" || event.PathParameters["id"] != "42" {
+					t.Errorf("StageVariables = %v, PathParameters = %v", event.StageVariables, event.PathParameters)
+				}
+				if event.Authorizer.JWTClaims["sub"] != "user-2" || event.Authorizer.JWTClaims["groups"] != "admin,ops" {
+					t.Errorf("JWTClaims = %v, expected later pairs to win", event.Authorizer.JWTClaims)
+				}
+				if event.Authorizer.Lambda["tenant"] != "acme" || event.Authorizer.IAMUserARN == "" || len(event.Authorizer.JWTScopes) != 1 {
+					t.Errorf("Authorizer = %+v", event.Authorizer)
+				}
+			},
+		},
+		{
+			name:    "pair without =",
+			context: config.LambdaContextConfig{Claims: []string{"sub"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid source IP",
+			context: config.LambdaContextConfig{SourceIP: "localhost"},
+			wantErr: true,
+		},
+		{
+			name:    "IAM ARN without account",
+			context: config.LambdaContextConfig{IAMUserARN: "alice"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := lambdaOptions(&config.Config{LambdaContext: tt.context})
+			if tt.wantErr {
+				if !errors.IsType(err, errors.ErrorTypeValidation) {
+					t.Fatalf("Expected validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.expected(t, options.Event)
+		})
+	}
+}
+
+func TestLambdaFunctionError(t *testing.T) {
+	fnErr := &qurlhttp.LambdaFunctionError{
+		Target:     qurlhttp.LambdaTarget{FunctionName: "my-fn", Qualifier: "prod"},
+		Kind:       "Unhandled",
+		Type:       "TypeError",
+		Message:    "boom",
+		StackTrace: []string{"at handler (index.js:1:1)"},
+	}
+
+	qErr, ok := lambdaFunctionError(fmt.Errorf("request failed: %w", fnErr), false)
+	if !ok {
+		t.Fatal("Expected a Lambda function error to be recognized")
+	}
+
+	if qErr.Type != errors.ErrorTypeLambda {
+		t.Errorf("Expected lambda error type, got %s", qErr.Type)
+	}
+	if qErr.Message != "Lambda function error (Unhandled): TypeError: boom" {
+		t.Errorf("Unexpected message %q", qErr.Message)
+	}
+	for key, expected := range map[string]interface{}{
+		"function":       "my-fn (qualifier: prod)",
+		"function_error": "Unhandled",
+		"error_type":     "TypeError",
+		"error_message":  "boom",
+	} {
+		if qErr.Context[key] != expected {
+			t.Errorf("Expected context %s=%v, got %v", key, expected, qErr.Context[key])
+		}
+	}
+	if _, ok := qErr.Context["suggestion"]; !ok {
+		t.Error("Expected a --lambda-logs suggestion when logs were not shown")
+	}
+
+	if _, ok := lambdaFunctionError(fmt.Errorf("connection refused"), false); ok {
+		t.Error("Expected other errors to be left alone")
+	}
+}
@@ -33,18 +33,13 @@ func (a *openAPIAdapter) BaseURL(ctx context.Context) (string, error) {
 	return a.viewer.BaseURL(ctx)
 }
 
-// GetServers returns the server URLs as strings from the OpenAPI specification
-func (a *openAPIAdapter) GetServers() ([]string, error) {
-	servers, err := a.viewer.GetServers()
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert server objects to string URLs
-	serverURLs := make([]string, len(servers))
-	for i, server := range servers {
-		serverURLs[i] = server.URL
-	}
-
-	return serverURLs, nil
+// MatchPath resolves a concrete request path to its OpenAPI operation
+func (a *openAPIAdapter) MatchPath(ctx context.Context, path, method string) (*openapi.PathMatch, error) {
+	return a.viewer.MatchPath(ctx, path, method)
+}
+
+// GetServers returns the servers from the OpenAPI specification,
+// with server variables expanded
+func (a *openAPIAdapter) GetServers(ctx context.Context) ([]openapi.ServerInfo, error) {
+	return a.viewer.ResolvedServers(ctx)
 }
\ No newline at end of file
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/pkg/openapi"
+)
+
+// ApplyPathParameters expands {name} placeholders in a path using name=value pairs
+// from the --path-param flag. Values are percent-encoded as path segments.
+// It fails if a placeholder is left without a value or a parameter matches no placeholder.
+func ApplyPathParameters(path string, pathParams []string) (string, error) {
+	values := make(map[string]string)
+	for _, param := range pathParams {
+		parts := strings.SplitN(param, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return "", errors.New(errors.ErrorTypeValidation, "invalid path parameter").
+				WithContext("param", param).
+				WithContext("suggestion", "use the format name=value (e.g., -p petId=123)")
+		}
+		values[strings.TrimSpace(parts[0])] = parts[1]
+	}
+
+	placeholders := openapi.TemplateParams(path)
+	if len(placeholders) == 0 && len(values) == 0 {
+		return path, nil
+	}
+
+	// Reject parameters that don't correspond to any placeholder (usually a typo)
+	known := make(map[string]bool)
+	for _, name := range placeholders {
+		known[name] = true
+	}
+	var unknown []string
+	for _, param := range pathParams {
+		name := strings.TrimSpace(strings.SplitN(param, "=", 2)[0])
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return "", errors.New(errors.ErrorTypeValidation, "unknown path parameters").
+			WithContext("path", path).
+			WithContext("unknown", unknown).
+			WithContext("available", placeholders)
+	}
+
+	var missing []string
+	for _, name := range placeholders {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", errors.New(errors.ErrorTypeValidation, "missing required path parameters").
+			WithContext("path", path).
+			WithContext("missing", missing).
+			WithContext("suggestion", "provide each with -p name=value")
+	}
+
+	// Only the path portion is expanded, leaving any query string untouched
+	return openapi.ExpandTemplate(path, func(name string) string {
+		return url.PathEscape(values[name])
+	}), nil
+}
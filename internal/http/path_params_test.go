@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+)
+
+func TestApplyPathParameters(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		params      []string
+		expected    string
+		wantMissing []string
+		wantError   bool
+	}{
+		{
+			name:     "no placeholders and no params",
+			path:     "/users",
+			expected: "/users",
+		},
+		{
+			name:     "single placeholder",
+			path:     "/pet/{petId}",
+			params:   []string{"petId=123"},
+			expected: "/pet/123",
+		},
+		{
+			name:     "multiple placeholders",
+			path:     "/users/{userId}/posts/{postId}",
+			params:   []string{"userId=42", "postId=7"},
+			expected: "/users/42/posts/7",
+		},
+		{
+			name:     "values are percent-encoded",
+			path:     "/files/{name}",
+			params:   []string{"name=my report/v1.pdf"},
+			expected: "/files/my%20report%2Fv1.pdf",
+		},
+		{
+			name:     "value may contain equals sign",
+			path:     "/tokens/{token}",
+			params:   []string{"token=abc=="},
+			expected: "/tokens/abc==",
+		},
+		{
+			name:     "query string is preserved",
+			path:     "/pet/{petId}?verbose=true",
+			params:   []string{"petId=1"},
+			expected: "/pet/1?verbose=true",
+		},
+		{
+			name:     "absolute URL",
+			path:     "https://api.example.com/pet/{petId}",
+			params:   []string{"petId=9"},
+			expected: "https://api.example.com/pet/9",
+		},
+		{
+			name:        "missing parameter",
+			path:        "/users/{userId}/posts/{postId}",
+			params:      []string{"userId=42"},
+			wantMissing: []string{"postId"},
+			wantError:   true,
+		},
+		{
+			name:        "all parameters missing",
+			path:        "/users/{userId}/posts/{postId}",
+			wantMissing: []string{"userId", "postId"},
+			wantError:   true,
+		},
+		{
+			name:      "unknown parameter",
+			path:      "/pet/{petId}",
+			params:    []string{"petId=1", "petID=1"},
+			wantError: true,
+		},
+		{
+			name:      "invalid format",
+			path:      "/pet/{petId}",
+			params:    []string{"petId"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyPathParameters(tt.path, tt.params)
+
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("ApplyPathParameters() expected error, got %q", result)
+				}
+				if !errors.IsType(err, errors.ErrorTypeValidation) {
+					t.Errorf("Expected validation error, got %v", errors.GetType(err))
+				}
+				if tt.wantMissing != nil {
+					missing := errors.GetContext(err)["missing"]
+					if !reflect.DeepEqual(missing, tt.wantMissing) {
+						t.Errorf("Missing = %v, want %v", missing, tt.wantMissing)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ApplyPathParameters() unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("ApplyPathParameters() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestURLResolver_PathParameters(t *testing.T) {
+	cfg := &config.Config{
+		Server:     "https://api.example.com/v1",
+		PathParams: []string{"petId=123"},
+	}
+	resolver := NewURLResolver(cfg, nil)
+
+	result, err := resolver.ResolveURL(context.Background(), "/pet/{petId}")
+	if err != nil {
+		t.Fatalf("ResolveURL() unexpected error: %v", err)
+	}
+	if result != "https://api.example.com/v1/pet/123" {
+		t.Errorf("ResolveURL() = %q, want %q", result, "https://api.example.com/v1/pet/123")
+	}
+
+	cfg.PathParams = nil
+	if _, err := resolver.ResolveURL(context.Background(), "/pet/{petId}"); err == nil {
+		t.Error("ResolveURL() expected error for missing path parameter")
+	}
+}
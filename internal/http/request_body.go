@@ -0,0 +1,112 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+)
+
+// requestBody returns the request body from --data, --data-raw, --data-binary or --form.
+// The body is loaded once and reused for every request built, since stdin can
+// only be read once.
+func (b *RequestBuilder) requestBody() ([]byte, error) {
+	if b.bodyLoaded {
+		return b.body, b.bodyErr
+	}
+	b.bodyLoaded = true
+
+	var sources []string
+	if b.config.Data != "" {
+		sources = append(sources, "--data")
+		b.body, b.bodyErr = readDataArgument(b.config.Data, b.stdin, false)
+	}
+	if b.config.DataRaw != "" {
+		sources = append(sources, "--data-raw")
+		b.body = []byte(b.config.DataRaw)
+	}
+	if b.config.DataBinary != "" {
+		sources = append(sources, "--data-binary")
+		b.body, b.bodyErr = readDataArgument(b.config.DataBinary, b.stdin, true)
+	}
+	if len(b.config.Form) > 0 {
+		sources = append(sources, "--form")
+		b.body, b.bodyContentType, b.bodyErr = buildMultipartBody(b.config.Form, b.stdin)
+	}
+
+	if len(sources) > 1 {
+		b.body = nil
+		b.bodyContentType = ""
+		b.bodyErr = errors.New(errors.ErrorTypeValidation, "multiple request bodies specified").
+			WithContext("flags", sources).
+			WithContext("suggestion", "use only one of --data, --data-raw, --data-binary and --form")
+	}
+
+	return b.body, b.bodyErr
+}
+
+// readDataArgument resolves a body argument using the curl conventions:
+// @path reads the file, @- reads stdin, anything else is used as-is.
+// Unless binary is set, carriage returns and newlines are stripped from file
+// contents, like curl does for -d.
+func readDataArgument(value string, stdin io.Reader, binary bool) ([]byte, error) {
+	if !strings.HasPrefix(value, "@") {
+		return []byte(value), nil
+	}
+
+	source := strings.TrimPrefix(value, "@")
+
+	var data []byte
+	var err error
+	if source == "-" {
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		data, err = io.ReadAll(stdin)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrorTypeValidation, "failed to read request body from stdin")
+		}
+	} else {
+		data, err = os.ReadFile(source)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrorTypeValidation, "failed to read request body file").
+				WithContext("path", source).
+				WithContext("suggestion", "check the path after @, or use --data-raw to send a literal @")
+		}
+	}
+
+	if !binary {
+		data = bytes.ReplaceAll(data, []byte("\r"), nil)
+		data = bytes.ReplaceAll(data, []byte("\n"), nil)
+	}
+
+	return data, nil
+}
+
+// peekBody returns the body of req without consuming it, using GetBody when
+// the request can replay its body and restoring req.Body otherwise
+func peekBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
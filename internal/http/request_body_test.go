@@ -0,0 +1,130 @@
+package http
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/rs/zerolog"
+)
+
+func TestRequestBuilder_RequestBody(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "body.json")
+	if err := os.WriteFile(jsonFile, []byte("{\r\n  \"name\": \"doggie\"\n}\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write body file: %v", err)
+	}
+	binaryFile := filepath.Join(dir, "body.bin")
+	if err := os.WriteFile(binaryFile, []byte{0x00, 0xff, '\n', 0x01}, 0o644); err != nil {
+		t.Fatalf("Failed to write body file: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		config          *config.Config
+		stdin           string
+		wantBody        string
+		wantContentType string
+		wantErrType     errors.ErrorType
+	}{
+		{
+			name:            "inline data",
+			config:          &config.Config{Data: `{"name":"doggie"}`},
+			wantBody:        `{"name":"doggie"}`,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "data from file strips newlines",
+			config:          &config.Config{Data: "@" + jsonFile},
+			wantBody:        `{  "name": "doggie"}`,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "data from stdin",
+			config:          &config.Config{Data: "@-"},
+			stdin:           "a=1\nb=2\n",
+			wantBody:        "a=1b=2",
+			wantContentType: "application/x-www-form-urlencoded",
+		},
+		{
+			name:     "data-binary keeps file bytes",
+			config:   &config.Config{DataBinary: "@" + binaryFile},
+			wantBody: "\x00\xff\n\x01",
+		},
+		{
+			name:     "data-binary from stdin",
+			config:   &config.Config{DataBinary: "@-"},
+			stdin:    "line1\nline2\n",
+			wantBody: "line1\nline2\n",
+		},
+		{
+			name:     "data-raw does not read files",
+			config:   &config.Config{DataRaw: "@" + jsonFile},
+			wantBody: "@" + jsonFile,
+		},
+		{
+			name:        "missing file",
+			config:      &config.Config{Data: "@" + filepath.Join(dir, "missing.json")},
+			wantErrType: errors.ErrorTypeValidation,
+		},
+		{
+			name:        "multiple body flags",
+			config:      &config.Config{Data: "a=1", DataRaw: "b=2"},
+			wantErrType: errors.ErrorTypeValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewRequestBuilder(zerolog.Nop(), tt.config, nil)
+			builder.stdin = strings.NewReader(tt.stdin)
+
+			req, err := builder.Build(context.Background(), "POST", "https://api.example.com/pet", "/pet")
+
+			if tt.wantErrType != "" {
+				if !errors.IsType(err, tt.wantErrType) {
+					t.Fatalf("Expected %s error, got %v", tt.wantErrType, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("Failed to read request body: %v", err)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, body)
+			}
+			if tt.wantContentType != "" && req.Header.Get("Content-Type") != tt.wantContentType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.wantContentType, req.Header.Get("Content-Type"))
+			}
+			if req.GetBody == nil {
+				t.Error("Expected request body to be replayable")
+			}
+		})
+	}
+}
+
+func TestRequestBuilder_RequestBodyReadOnce(t *testing.T) {
+	builder := NewRequestBuilder(zerolog.Nop(), &config.Config{Data: "@-"}, nil)
+	builder.stdin = strings.NewReader("payload")
+
+	for i := 0; i < 2; i++ {
+		req, err := builder.Build(context.Background(), "POST", "https://api.example.com/pet", "/pet")
+		if err != nil {
+			t.Fatalf("Build %d: unexpected error: %v", i, err)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if string(body) != "payload" {
+			t.Errorf("Build %d: expected body %q, got %q", i, "payload", body)
+		}
+	}
+}
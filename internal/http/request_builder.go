@@ -1,11 +1,14 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/brendan.keane/qurl/internal/errors"
 	internalconfig "github.com/brendan.keane/qurl/internal/config"
+	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
 	"github.com/rs/zerolog"
 )
 
@@ -21,6 +25,13 @@ type RequestBuilder struct {
 	logger  zerolog.Logger
 	config  *internalconfig.Config
 	openapi OpenAPIProvider
+	stdin   io.Reader // Source for @- request bodies
+
+	// Request body, loaded on first Build and reused afterwards
+	body            []byte
+	bodyContentType string // Set for bodies that carry their own type, e.g. multipart boundaries
+	bodyErr         error
+	bodyLoaded      bool
 }
 
 // NewRequestBuilder creates a new request builder
@@ -29,6 +40,7 @@ func NewRequestBuilder(logger zerolog.Logger, cfg *internalconfig.Config, openap
 		logger:  logger.With().Str("component", "request_builder").Logger(),
 		config:  cfg,
 		openapi: openapi,
+		stdin:   os.Stdin,
 	}
 }
 
@@ -39,12 +51,18 @@ func (b *RequestBuilder) Build(ctx context.Context, method, targetURL, originalP
 		Str("target_url", targetURL).
 		Logger()
 
-	// Create the request with body if data is provided
+	body, err := b.requestBody()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the request with body if data is provided. A bytes.Reader lets
+	// net/http replay the body on redirects.
 	var requestBody io.Reader
-	if b.config.Data != "" {
-		requestBody = strings.NewReader(b.config.Data)
+	if len(body) > 0 {
+		requestBody = bytes.NewReader(body)
 		logger.Debug().
-			Int("body_length", len(b.config.Data)).
+			Int("body_length", len(body)).
 			Msg("request body added")
 	}
 
@@ -60,10 +78,7 @@ func (b *RequestBuilder) Build(ctx context.Context, method, targetURL, originalP
 
 	// Set headers based on OpenAPI spec if available
 	if b.openapi != nil && originalPath != "" {
-		headerCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
-
-		if err := b.openapi.SetHeaders(headerCtx, req, originalPath, method); err != nil {
+		if err := b.openapi.SetHeaders(ctx, req, originalPath, method); err != nil {
 			// Log warning but continue - headers are not critical
 			logger.Warn().
 				Err(err).
@@ -74,10 +89,15 @@ func (b *RequestBuilder) Build(ctx context.Context, method, targetURL, originalP
 	}
 
 	// Apply authentication
-	if err := b.applyAuthentication(ctx, req, targetURL); err != nil {
+	if err := b.applyAuthentication(ctx, req, targetURL, body); err != nil {
 		return nil, errors.Wrap(err, errors.ErrorTypeAuth, "failed to apply authentication")
 	}
 
+	// Resume a Server-Sent Events stream
+	if b.config.SSE.LastEventID != "" {
+		req.Header.Set("Last-Event-ID", b.config.SSE.LastEventID)
+	}
+
 	// Set custom headers from -H flags (these override any headers set above)
 	headerCount := 0
 	for _, header := range b.config.Headers {
@@ -106,23 +126,125 @@ func (b *RequestBuilder) Build(ctx context.Context, method, targetURL, originalP
 	}
 
 	// Set Content-Type header if data is provided and no custom Content-Type was set
-	if b.config.Data != "" && req.Header.Get("Content-Type") == "" {
-		contentType := b.detectContentType(b.config.Data)
+	if len(body) > 0 && req.Header.Get("Content-Type") == "" {
+		contentType := b.bodyContentType
+		if contentType == "" {
+			contentType = b.detectContentType(string(body))
+		}
 		req.Header.Set("Content-Type", contentType)
 		logger.Debug().
 			Str("content_type", contentType).
 			Msg("content type auto-detected")
 	}
 
+	// Check the finished request against the OpenAPI operation before it goes
+	// out. Forms are checked whenever a spec is loaded, since servers tend to
+	// ignore a misspelled field rather than reject it.
+	if b.config.ValidateRequest {
+		if err := b.validateRequest(ctx, req, originalPath, body); err != nil {
+			return nil, err
+		}
+		logger.Debug().Msg("request matches OpenAPI operation")
+	} else if len(b.config.Form) > 0 && b.openapi != nil {
+		if err := b.validateFormBody(ctx, req, originalPath, body); err != nil {
+			return nil, err
+		}
+	}
+
 	return req, nil
 }
 
+// validateRequest checks query parameters, headers and body of a built request
+// against the OpenAPI operation matching the request path and method
+func (b *RequestBuilder) validateRequest(ctx context.Context, req *http.Request, originalPath string, body []byte) error {
+	if b.openapi == nil {
+		return errors.New(errors.ErrorTypeConfig, "OpenAPI URL is required for request validation").
+			WithContext("config_type", "openapi").
+			WithContext("suggestion", "set --openapi or QURL_OPENAPI, or drop --validate")
+	}
+
+	path := b.validationPath(ctx, originalPath)
+
+	match, err := b.openapi.MatchPath(ctx, path, req.Method)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeOpenAPI, "failed to load OpenAPI spec for validation")
+	}
+	if match == nil {
+		return errors.New(errors.ErrorTypeValidation, "no OpenAPI operation matches the request").
+			WithContext("method", req.Method).
+			WithContext("path", path).
+			WithContext("suggestion", "use --docs to list the available endpoints")
+	}
+
+	violations := match.ValidateRequest(req.URL.Query(), req.Header, body)
+	if len(violations) > 0 {
+		return errors.New(errors.ErrorTypeValidation, "request does not match the OpenAPI operation").
+			WithContext("operation", fmt.Sprintf("%s %s", match.Method, match.Path)).
+			WithContext("violations", violations)
+	}
+
+	return nil
+}
+
+// validateFormBody checks a multipart body built from -F fields against the
+// matching OpenAPI operation. Unlike --validate, a request with no matching
+// operation, or a spec that cannot be loaded, is sent unchecked.
+func (b *RequestBuilder) validateFormBody(ctx context.Context, req *http.Request, originalPath string, body []byte) error {
+	path := b.validationPath(ctx, originalPath)
+
+	match, err := b.openapi.MatchPath(ctx, path, req.Method)
+	if err != nil || match == nil {
+		b.logger.Debug().Err(err).Str("path", path).Msg("form not checked: no OpenAPI operation")
+		return nil
+	}
+
+	violations := match.ValidateBody(req.Header.Get("Content-Type"), body)
+	if len(violations) > 0 {
+		return errors.New(errors.ErrorTypeValidation, "form does not match the OpenAPI operation").
+			WithContext("operation", fmt.Sprintf("%s %s", match.Method, match.Path)).
+			WithContext("violations", violations).
+			WithContext("suggestion", "use --docs to see the fields the operation accepts")
+	}
+
+	b.logger.Debug().Msg("form matches OpenAPI operation")
+	return nil
+}
+
+// validationPath returns the spec-relative path to match: path parameters are
+// expanded, and absolute URLs are reduced to their path below the spec's base URL
+func (b *RequestBuilder) validationPath(ctx context.Context, originalPath string) string {
+	path := originalPath
+	if expanded, err := ApplyPathParameters(path, b.config.PathParams); err == nil {
+		path = expanded
+	}
+
+	if !strings.Contains(path, "://") {
+		return path
+	}
+
+	parsed, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	path = parsed.Path
+
+	if baseURL, err := b.openapi.BaseURL(ctx); err == nil {
+		if base, err := url.Parse(baseURL); err == nil && base.Path != "" && base.Path != "/" {
+			if trimmed := strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/")); trimmed != path {
+				path = trimmed
+			}
+		}
+	}
+
+	return path
+}
+
 // applyAuthentication applies authentication to the request based on configuration
-func (b *RequestBuilder) applyAuthentication(ctx context.Context, req *http.Request, targetURL string) error {
+func (b *RequestBuilder) applyAuthentication(ctx context.Context, req *http.Request, targetURL string, body []byte) error {
 	logger := b.logger.With().Str("component", "auth").Logger()
 
 	// Check if this is a lambda:// URL - skip SigV4 for direct invocation
-	if strings.HasPrefix(targetURL, "lambda://") {
+	if qurlhttp.IsLambdaURL(targetURL) {
 		logger.Debug().Msg("lambda URL detected, skipping SigV4")
 		return nil
 	}
@@ -133,7 +255,7 @@ func (b *RequestBuilder) applyAuthentication(ctx context.Context, req *http.Requ
 			Str("service", b.config.SigV4Service).
 			Msg("applying AWS SigV4 signature")
 
-		if err := b.applySigV4(ctx, req); err != nil {
+		if err := b.applySigV4(ctx, req, body); err != nil {
 			return errors.Wrap(err, errors.ErrorTypeAuth, "SigV4 signing failed")
 		}
 
@@ -143,8 +265,8 @@ func (b *RequestBuilder) applyAuthentication(ctx context.Context, req *http.Requ
 	return nil
 }
 
-// applySigV4 applies AWS SigV4 signing to the request
-func (b *RequestBuilder) applySigV4(ctx context.Context, req *http.Request) error {
+// applySigV4 applies AWS SigV4 signing to the request, hashing body as the payload
+func (b *RequestBuilder) applySigV4(ctx context.Context, req *http.Request, body []byte) error {
 	service := b.config.SigV4Service
 
 	// Load AWS config from default credential chain
@@ -171,26 +293,9 @@ func (b *RequestBuilder) applySigV4(ctx context.Context, req *http.Request) erro
 	// Create signer
 	signer := v4.NewSigner()
 
-	// Calculate payload hash for the signature
-	var payloadHash string
-	if req.Body != nil {
-		// Read body to calculate hash
-		bodyBytes, err := io.ReadAll(req.Body)
-		if err != nil {
-			return errors.Wrap(err, errors.ErrorTypeInternal, "failed to read request body for signing")
-		}
-
-		// Calculate SHA256 hash
-		hash := sha256.Sum256(bodyBytes)
-		payloadHash = fmt.Sprintf("%x", hash)
-
-		// Restore body for actual request
-		req.Body = io.NopCloser(strings.NewReader(string(bodyBytes)))
-	} else {
-		// Empty body hash
-		hash := sha256.Sum256([]byte{})
-		payloadHash = fmt.Sprintf("%x", hash)
-	}
+	// Calculate payload hash for the signature (an empty body hashes to the empty-string hash)
+	hash := sha256.Sum256(body)
+	payloadHash := fmt.Sprintf("%x", hash)
 
 	// Sign the request
 	err = signer.SignHTTP(ctx, creds, req, payloadHash, service, region, time.Now())
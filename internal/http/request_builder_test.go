@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/pkg/openapi"
+	"github.com/rs/zerolog"
+)
+
+const builderTestSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Builder API", "version": "1.0.0"},
+	"paths": {
+		"/pet/{petId}": {
+			"put": {
+				"parameters": [
+					{"name": "petId", "in": "path", "required": true, "schema": {"type": "integer"}},
+					{"name": "dryRun", "in": "query", "schema": {"type": "boolean"}}
+				],
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"required": ["name"],
+								"properties": {"name": {"type": "string"}}
+							}
+						}
+					}
+				},
+				"responses": {"200": {"description": "OK"}}
+			}
+		}
+	}
+}`
+
+func TestRequestBuilder_ValidateRequest(t *testing.T) {
+	parser := openapi.NewParser()
+	if err := parser.LoadFromBytes([]byte(builderTestSpec)); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		config        *config.Config
+		path          string
+		targetURL     string
+		noOpenAPI     bool
+		wantErrType   errors.ErrorType
+		wantViolation string
+	}{
+		{
+			name: "valid request",
+			config: &config.Config{
+				ValidateRequest: true,
+				Data:            `{"name": "doggie"}`,
+			},
+			path:      "/pet/1",
+			targetURL: "https://api.example.com/pet/1?dryRun=true",
+		},
+		{
+			name: "path parameters are expanded before matching",
+			config: &config.Config{
+				ValidateRequest: true,
+				PathParams:      []string{"petId=1"},
+				Data:            `{"name": "doggie"}`,
+			},
+			path:      "/pet/{petId}",
+			targetURL: "https://api.example.com/pet/1",
+		},
+		{
+			name: "query parameter type violation",
+			config: &config.Config{
+				ValidateRequest: true,
+				Data:            `{"name": "doggie"}`,
+			},
+			path:          "/pet/1",
+			targetURL:     "https://api.example.com/pet/1?dryRun=maybe",
+			wantErrType:   errors.ErrorTypeValidation,
+			wantViolation: "/query/dryRun",
+		},
+		{
+			name: "body violation",
+			config: &config.Config{
+				ValidateRequest: true,
+				Data:            `{"name": 7}`,
+			},
+			path:          "/pet/1",
+			targetURL:     "https://api.example.com/pet/1",
+			wantErrType:   errors.ErrorTypeValidation,
+			wantViolation: "/body/name",
+		},
+		{
+			name: "validation disabled",
+			config: &config.Config{
+				Data: `{"name": 7}`,
+			},
+			path:      "/pet/1",
+			targetURL: "https://api.example.com/pet/1",
+		},
+		{
+			name: "validation requires a spec",
+			config: &config.Config{
+				ValidateRequest: true,
+			},
+			path:        "/pet/1",
+			targetURL:   "https://api.example.com/pet/1",
+			noOpenAPI:   true,
+			wantErrType: errors.ErrorTypeConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := parser.MatchPath("/pet/1", "PUT")
+			if err != nil || match == nil {
+				t.Fatalf("Failed to match test operation: %v", err)
+			}
+
+			var provider OpenAPIProvider
+			if !tt.noOpenAPI {
+				provider = &mockOpenAPIProvider{match: match}
+			}
+
+			builder := NewRequestBuilder(zerolog.New(nil), tt.config, provider)
+			_, err = builder.Build(context.Background(), "PUT", tt.targetURL, tt.path)
+
+			if tt.wantErrType == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.IsType(err, tt.wantErrType) {
+				t.Fatalf("Expected %s error, got %v", tt.wantErrType, err)
+			}
+			if tt.wantViolation == "" {
+				return
+			}
+
+			violations, ok := errors.GetContext(err)["violations"].([]openapi.Violation)
+			if !ok || len(violations) != 1 || violations[0].Pointer != tt.wantViolation {
+				t.Errorf("Expected single violation at %s, got %v", tt.wantViolation, errors.GetContext(err)["violations"])
+			}
+		})
+	}
+}
+
+func TestRequestBuilder_ValidateRequestNoOperation(t *testing.T) {
+	cfg := &config.Config{ValidateRequest: true}
+	builder := NewRequestBuilder(zerolog.New(nil), cfg, &mockOpenAPIProvider{})
+
+	_, err := builder.Build(context.Background(), "GET", "https://api.example.com/unknown", "/unknown")
+	if !errors.IsType(err, errors.ErrorTypeValidation) {
+		t.Fatalf("Expected validation error for unknown operation, got %v", err)
+	}
+}
@@ -1,13 +1,18 @@
 package http
 
 import (
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/brendan.keane/qurl/internal/config"
 	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/internal/filter"
+	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
 	"github.com/rs/zerolog"
 )
 
@@ -39,30 +44,61 @@ func (h *responseHandler) HandleResponse(resp *http.Response, method, targetURL
 		h.showRequestDetails(method, targetURL, resp.Request)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	out, closeOutput, err := h.openOutput(resp.ContentLength)
 	if err != nil {
-		logger.Error().Err(err).Msg("failed to read response body")
-		return errors.Wrap(err, errors.ErrorTypeNetwork, "failed to read response body")
+		return err
 	}
 
-	logger.Debug().
-		Int("body_length", len(body)).
-		Msg("response body read")
-
-	// Print response details based on flags
+	// Print response details based on flags. Included headers go with the
+	// body, as with curl -i -o.
 	if h.config.Verbose {
 		h.showResponseDetails(resp)
 	} else if h.config.IncludeHeaders {
-		h.showResponseHeaders(resp)
+		h.showResponseHeaders(out, resp)
 	}
 
-	// Always print response body
-	fmt.Print(string(body))
+	// Apply a response filter, then pretty-print JSON, YAML and XML for a
+	// terminal. Other bodies are copied as they arrive so streamed responses
+	// are shown incrementally and large ones are not held in memory.
+	var body io.Reader = resp.Body
+	contentType := resp.Header.Get("Content-Type")
+	if language, _ := h.config.Query(); language != "" || h.config.Grep != "" {
+		body, contentType, err = h.filterBody(resp.Body, contentType)
+	}
+	if formatter := newResponseFormatter(h.config.Raw, h.config.Output); formatter != nil && err == nil {
+		body, err = formatter.formatBody(contentType, body)
+	}
+
+	var written int64
+	if err == nil {
+		written, err = io.Copy(out, body)
+	}
+	if closeErr := closeOutput(); closeErr != nil && err == nil {
+		return errors.Wrap(closeErr, errors.ErrorTypeInternal, "failed to write output file").
+			WithContext("file", h.config.Output)
+	}
+	if err != nil {
+		if errors.IsType(err, errors.ErrorTypeValidation) {
+			logger.Error().Err(err).Msg("failed to filter response")
+			return err
+		}
+		logger.Error().Err(err).Msg("failed to read response body")
+		if fnErr, ok := lambdaFunctionError(err, h.config.LambdaLogs); ok {
+			return fnErr
+		}
+		if timeoutErr := interruptedError(err, targetURL); timeoutErr != nil {
+			return timeoutErr
+		}
+		var qErr *errors.QUrlError
+		if stderrors.As(err, &qErr) {
+			return qErr
+		}
+		return errors.Wrap(err, errors.ErrorTypeNetwork, "failed to read response body")
+	}
 
 	// Log response summary
 	logger.Debug().
-		Int("body_length", len(body)).
+		Int64("body_length", written).
 		Bool("verbose", h.config.Verbose).
 		Bool("include_headers", h.config.IncludeHeaders).
 		Msg("response displayed")
@@ -70,6 +106,80 @@ func (h *responseHandler) HandleResponse(resp *http.Response, method, targetURL
 	return nil
 }
 
+// filterBody applies --jmespath, --jsonpath, --jq or --grep to the body,
+// returning the result and its content type. The whole body is read, since
+// every filter needs all of it.
+func (h *responseHandler) filterBody(body io.Reader, contentType string) (io.Reader, string, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, contentType, err
+	}
+
+	var result *filter.Result
+	if language, expression := h.config.Query(); language != "" {
+		result, err = filter.Search(language, string(data), expression)
+		contentType = "application/json"
+		if err != nil {
+			return nil, contentType, errors.Wrap(err, errors.ErrorTypeValidation, "failed to filter response").
+				WithContext("language", language).
+				WithContext("expression", expression)
+		}
+	} else {
+		result, err = filter.Regex(string(data), h.config.Grep, h.config.Context)
+		contentType = "text/plain"
+		if err != nil {
+			return nil, contentType, errors.Wrap(err, errors.ErrorTypeValidation, "failed to filter response").
+				WithContext("grep", h.config.Grep)
+		}
+	}
+
+	h.logger.Debug().Interface("filter", result.Meta).Msg("response filtered")
+
+	if result.Content == "" {
+		return strings.NewReader(""), contentType, nil
+	}
+	return strings.NewReader(result.Content + "\n"), contentType, nil
+}
+
+// HandleEventStream prints Server-Sent Events as they arrive, recording the
+// last event ID and retry delay in state for reconnects. It returns nil when
+// the server ends the stream.
+func (h *responseHandler) HandleEventStream(resp *http.Response, method, targetURL string, state *EventStreamState) error {
+	// Headers are shown for the first connection only
+	if state.Reconnects == 0 {
+		if h.config.Verbose {
+			h.showRequestDetails(method, targetURL, resp.Request)
+			h.showResponseDetails(resp)
+		} else if h.config.IncludeHeaders {
+			h.showResponseHeaders(os.Stdout, resp)
+		}
+	}
+
+	reader := newSSEReader(resp.Body, state.LastEventID)
+	defer func() {
+		state.LastEventID = reader.lastID
+		if reader.retry > 0 {
+			state.Retry = reader.retry
+		}
+	}()
+
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			h.logger.Debug().Int("events", state.Events).Msg("event stream ended")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		state.Events++
+		if err := writeEvent(os.Stdout, event, h.config.SSE.NDJSON); err != nil {
+			return errors.Wrap(err, errors.ErrorTypeInternal, "failed to write event")
+		}
+	}
+}
+
 // HandleResponseForMCP processes the HTTP response and returns structured data
 // This is used by the MCP server to capture response without printing to stdout
 func (h *responseHandler) HandleResponseForMCP(resp *http.Response, method, targetURL string) (string, map[string][]string, int, error) {
@@ -82,6 +192,9 @@ func (h *responseHandler) HandleResponseForMCP(resp *http.Response, method, targ
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to read response body")
+		if timeoutErr := interruptedError(err, targetURL); timeoutErr != nil {
+			return "", nil, resp.StatusCode, timeoutErr
+		}
 		return "", nil, resp.StatusCode, errors.Wrap(err, errors.ErrorTypeNetwork, "failed to read response body")
 	}
 
@@ -97,6 +210,13 @@ func (h *responseHandler) showRequestDetails(method, targetURL string, req *http
 	// Print request line to stderr
 	fmt.Fprintf(os.Stderr, "%s %s\n", method, targetURL)
 
+	// Show which function version a lambda:// URL resolved to
+	if req != nil && qurlhttp.IsLambdaURL(req.URL.String()) {
+		if target, err := qurlhttp.ParseLambdaTarget(req.URL); err == nil {
+			fmt.Fprintf(os.Stderr, "Lambda: %s\n", target)
+		}
+	}
+
 	// Print headers to stderr if request is available
 	if req != nil {
 		for key, values := range req.Header {
@@ -107,8 +227,14 @@ func (h *responseHandler) showRequestDetails(method, targetURL string, req *http
 	}
 
 	// Print request body if available
-	if h.config.Data != "" {
-		fmt.Fprintf(os.Stderr, "\n%s\n", h.config.Data)
+	if req != nil {
+		if body, err := peekBody(req); err == nil && len(body) > 0 {
+			if utf8.Valid(body) {
+				fmt.Fprintf(os.Stderr, "\n%s\n", body)
+			} else {
+				fmt.Fprintf(os.Stderr, "\n[%d bytes of binary data]\n", len(body))
+			}
+		}
 	}
 
 	fmt.Fprintf(os.Stderr, "\n")
@@ -129,13 +255,13 @@ func (h *responseHandler) showResponseDetails(resp *http.Response) {
 	fmt.Fprintf(os.Stderr, "\n")
 }
 
-// showResponseHeaders displays response headers for include mode
-func (h *responseHandler) showResponseHeaders(resp *http.Response) {
-	fmt.Printf("HTTP/%d.%d %s\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
+// showResponseHeaders writes response headers to w for include mode
+func (h *responseHandler) showResponseHeaders(w io.Writer, resp *http.Response) {
+	fmt.Fprintf(w, "HTTP/%d.%d %s\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
 	for key, values := range resp.Header {
 		for _, value := range values {
-			fmt.Printf("%s: %s\n", key, value)
+			fmt.Fprintf(w, "%s: %s\n", key, value)
 		}
 	}
-	fmt.Println() // Empty line between headers and body
+	fmt.Fprintln(w) // Empty line between headers and body
 }
\ No newline at end of file
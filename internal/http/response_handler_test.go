@@ -0,0 +1,71 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleResponseFilters(t *testing.T) {
+	pets := `[{"name":"doggie","status":"available"},{"name":"kitty","status":"sold"}]`
+	logs := "INFO start\nERROR disk full\nINFO done"
+
+	tests := []struct {
+		name     string
+		body     string
+		jmespath string
+		jsonpath string
+		jq       string
+		grep     string
+		want     string
+		wantErr  bool
+	}{
+		{name: "no filter", body: pets, want: pets},
+		{name: "jmespath", body: pets, jmespath: "[].name", want: "[\n  \"doggie\",\n  \"kitty\"\n]\n"},
+		{name: "jmespath with no result", body: pets, jmespath: "[0].owner", want: "null\n"},
+		{name: "jmespath on a non-JSON body", body: logs, jmespath: "[].name", wantErr: true},
+		{name: "invalid jmespath", body: pets, jmespath: "[?", wantErr: true},
+		{name: "jsonpath", body: pets, jsonpath: "$[?(@.status == 'sold')].name", want: "[\n  \"kitty\"\n]\n"},
+		{name: "jq stream", body: pets, jq: ".[] | select(.status == \"available\") | .name", want: "\"doggie\"\n"},
+		{name: "jq with several outputs", body: pets, jq: ".[].name", want: "\"doggie\"\n\"kitty\"\n"},
+		{name: "jq reduce", body: pets, jq: "reduce .[] as $p (0; . + 1)", want: "2\n"},
+		{name: "jq runtime error", body: pets, jq: ".[0].name + 1", wantErr: true},
+		{name: "grep", body: logs, grep: "ERROR", want: "=== Context Window 1 (bytes 0-36) ===\n" + logs + "\n"},
+		{name: "grep with no matches", body: logs, grep: "WARN", want: ""},
+		{name: "invalid grep pattern", body: logs, grep: "(", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries := map[string]string{"jmespath": tt.jmespath, "jsonpath": tt.jsonpath, "jq": tt.jq}
+			cfg := &config.Config{Queries: queries, Grep: tt.grep, Context: 1}
+			handler := NewResponseHandler(zerolog.Nop(), cfg)
+
+			resp := &http.Response{
+				StatusCode: 200,
+				Status:     "200 OK",
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			var err error
+			output := captureStdout(t, func() {
+				err = handler.HandleResponse(resp, "GET", "https://api.example.com/pets")
+			})
+
+			if tt.wantErr {
+				assert.True(t, errors.IsType(err, errors.ErrorTypeValidation), "expected validation error, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+}
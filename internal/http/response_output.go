@@ -0,0 +1,171 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+)
+
+// parseByteSize parses a --max-filesize value: a byte count with an optional
+// K, M or G suffix (powers of 1024). An empty value means no limit.
+func parseByteSize(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	number := strings.TrimSuffix(strings.ToUpper(value), "B")
+	switch {
+	case strings.HasSuffix(number, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(number, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(number, "G"):
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		number = number[:len(number)-1]
+	}
+
+	size, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || size <= 0 {
+		return 0, errors.New(errors.ErrorTypeValidation, "invalid size").
+			WithContext("field", "max-filesize").
+			WithContext("value", value).
+			WithContext("suggestion", "use a positive byte count, optionally with a K, M or G suffix (e.g. 10M)")
+	}
+	return size * multiplier, nil
+}
+
+// limitResponseBody makes reading resp fail once more than maxSize bytes have
+// arrived. A Content-Length over the limit fails before anything is read.
+func limitResponseBody(resp *http.Response, maxSize int64, targetURL string) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	if resp.ContentLength > maxSize {
+		resp.Body.Close()
+		return fileTooLargeError(maxSize, targetURL).
+			WithContext("content_length", resp.ContentLength)
+	}
+
+	resp.Body = &maxSizeBody{ReadCloser: resp.Body, remaining: maxSize, maxSize: maxSize, url: targetURL}
+	return nil
+}
+
+// fileTooLargeError reports a body over --max-filesize
+func fileTooLargeError(maxSize int64, targetURL string) *errors.QUrlError {
+	return errors.New(errors.ErrorTypeValidation, "response body exceeds --max-filesize").
+		WithContext("max_filesize", maxSize).
+		WithContext("url", targetURL)
+}
+
+// maxSizeBody passes through the first maxSize bytes of a body and fails
+// when there are more
+type maxSizeBody struct {
+	io.ReadCloser
+	remaining int64
+	maxSize   int64
+	url       string
+}
+
+func (b *maxSizeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = 0
+		return n, fileTooLargeError(b.maxSize, b.url)
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// openOutput returns the destination for the response body: stdout, or the
+// --output file. Writes to a file report progress on stderr when it is a terminal.
+func (h *responseHandler) openOutput(contentLength int64) (io.Writer, func() error, error) {
+	if h.config.Output == "" || h.config.Output == "-" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	file, err := os.Create(h.config.Output)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrorTypeConfig, "failed to create output file").
+			WithContext("file", h.config.Output)
+	}
+
+	if !isTerminal(os.Stderr) {
+		return file, file.Close, nil
+	}
+
+	progress := newProgressWriter(os.Stderr, contentLength)
+	closeOutput := func() error {
+		progress.Finish()
+		return file.Close()
+	}
+	return io.MultiWriter(file, progress), closeOutput, nil
+}
+
+// isTerminal reports whether f is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// progressWriter counts the bytes written through it and redraws a progress
+// line on w at most every interval
+type progressWriter struct {
+	w        io.Writer
+	total    int64 // Expected size, or -1 when unknown
+	written  int64
+	interval time.Duration
+	lastDraw time.Time
+	start    time.Time
+}
+
+func newProgressWriter(w io.Writer, total int64) *progressWriter {
+	return &progressWriter{w: w, total: total, interval: 100 * time.Millisecond, start: time.Now()}
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	if now := time.Now(); now.Sub(p.lastDraw) >= p.interval {
+		p.lastDraw = now
+		p.draw()
+	}
+	return len(b), nil
+}
+
+// Finish draws the final count and ends the progress line
+func (p *progressWriter) Finish() {
+	p.draw()
+	fmt.Fprintf(p.w, " in %s\n", time.Since(p.start).Round(time.Millisecond))
+}
+
+func (p *progressWriter) draw() {
+	if p.total > 0 {
+		fmt.Fprintf(p.w, "\r%s / %s (%d%%)", formatBytes(p.written), formatBytes(p.total), p.written*100/p.total)
+		return
+	}
+	fmt.Fprintf(p.w, "\r%s", formatBytes(p.written))
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. 1.5 MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
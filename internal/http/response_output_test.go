@@ -0,0 +1,145 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "1024", want: 1024},
+		{value: "500K", want: 500 << 10},
+		{value: "10M", want: 10 << 20},
+		{value: "10mb", want: 10 << 20},
+		{value: "2G", want: 2 << 30},
+		{value: "0", wantErr: true},
+		{value: "-5M", wantErr: true},
+		{value: "ten", wantErr: true},
+		{value: "5T", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseByteSize(tt.value)
+			if tt.wantErr {
+				assert.True(t, errors.IsType(err, errors.ErrorTypeValidation), "expected validation error, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLimitResponseBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		maxSize       int64
+		wantBody      string
+		wantErr       bool
+		wantReadErr   bool
+	}{
+		{name: "no limit", body: "0123456789", contentLength: -1, wantBody: "0123456789"},
+		{name: "under limit", body: "0123456789", contentLength: 10, maxSize: 10, wantBody: "0123456789"},
+		{name: "content length over limit", body: "0123456789", contentLength: 10, maxSize: 5, wantErr: true},
+		{name: "streamed body over limit", body: "0123456789", contentLength: -1, maxSize: 5, wantBody: "01234", wantReadErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Body:          io.NopCloser(strings.NewReader(tt.body)),
+				ContentLength: tt.contentLength,
+			}
+
+			err := limitResponseBody(resp, tt.maxSize, "https://api.example.com/big")
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.IsType(err, errors.ErrorTypeValidation))
+				return
+			}
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			assert.Equal(t, tt.wantBody, string(body))
+			if tt.wantReadErr {
+				assert.True(t, errors.IsType(err, errors.ErrorTypeValidation), "expected validation error, got %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandleResponseOutputFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "report.json")
+	cfg := &config.Config{Output: output, IncludeHeaders: true}
+	handler := NewResponseHandler(zerolog.Nop(), cfg)
+
+	resp := &http.Response{
+		StatusCode: 200,
+		Status:     "200 OK",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"rows": 3}`)),
+	}
+
+	require.NoError(t, handler.HandleResponse(resp, "GET", "https://api.example.com/report"))
+
+	written, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1 200 OK\nContent-Type: application/json\n\n{\"rows\": 3}", string(written))
+}
+
+func TestProgressWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int64
+		want  string
+	}{
+		{name: "known size", total: 2048, want: "\r2.0 KiB / 2.0 KiB (100%)"},
+		{name: "unknown size", total: -1, want: "\r2.0 KiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			progress := newProgressWriter(&stderr, tt.total)
+
+			_, err := io.Copy(progress, bytes.NewReader(make([]byte, 2048)))
+			require.NoError(t, err)
+			progress.Finish()
+
+			lines := strings.Split(strings.TrimSuffix(stderr.String(), "\n"), "\n")
+			require.Len(t, lines, 1, "progress should stay on one line")
+			assert.True(t, strings.HasSuffix(strings.SplitN(lines[0], " in ", 2)[0], tt.want),
+				"expected final progress %q, got %q", tt.want, lines[0])
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "10.0 MiB", formatBytes(10<<20))
+	assert.Equal(t, "2.0 GiB", formatBytes(2<<30))
+}
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
+)
+
+const (
+	// retryBaseDelay is the backoff before the first retry; it doubles per attempt
+	retryBaseDelay = time.Second
+	// retryMaxDelay caps the backoff between attempts
+	retryMaxDelay = 30 * time.Second
+)
+
+// defaultRetryStatuses are the transient status codes retried when --retry-on is not set
+var defaultRetryStatuses = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// idempotentMethods are retried by default; others need --retry-all-methods
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// retryPolicy decides whether and when a failed attempt is retried
+type retryPolicy struct {
+	maxRetries int
+	maxTime    time.Duration
+	statuses   map[int]bool
+	allMethods bool
+}
+
+// newRetryPolicy builds the policy for the --retry flags
+func newRetryPolicy(cfg config.RetryConfig) (*retryPolicy, error) {
+	if cfg.Retries < 0 {
+		return nil, errors.New(errors.ErrorTypeValidation, "retry count must not be negative").
+			WithContext("retry", cfg.Retries)
+	}
+	if cfg.MaxTime < 0 {
+		return nil, errors.New(errors.ErrorTypeValidation, "retry max time must not be negative").
+			WithContext("retry_max_time", cfg.MaxTime.String())
+	}
+
+	policy := &retryPolicy{
+		maxRetries: cfg.Retries,
+		maxTime:    cfg.MaxTime,
+		statuses:   make(map[int]bool),
+		allMethods: cfg.AllMethods,
+	}
+
+	if len(cfg.On) == 0 {
+		for _, status := range defaultRetryStatuses {
+			policy.statuses[status] = true
+		}
+		return policy, nil
+	}
+
+	for _, value := range cfg.On {
+		status, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || status < 100 || status > 599 {
+			return nil, errors.New(errors.ErrorTypeValidation, "invalid retry status code").
+				WithContext("retry_on", value).
+				WithContext("suggestion", "use HTTP status codes (e.g., --retry-on 429,503)")
+		}
+		policy.statuses[status] = true
+	}
+
+	return policy, nil
+}
+
+// allows reports whether requests with method may be retried
+func (p *retryPolicy) allows(method string) bool {
+	return p.maxRetries > 0 && (p.allMethods || idempotentMethods[method])
+}
+
+// shouldRetry reports whether an attempt's outcome is transient
+func (p *retryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return isTransientError(err)
+	}
+	return p.statuses[resp.StatusCode]
+}
+
+// delay returns the wait before retry number attempt (starting at 0): the
+// response's Retry-After if it has one, else exponential backoff with jitter
+func (p *retryPolicy) delay(attempt int, resp *http.Response, now time.Time) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			return wait
+		}
+	}
+
+	backoff := retryMaxDelay
+	if attempt < 16 {
+		backoff = min(retryBaseDelay<<attempt, retryMaxDelay)
+	}
+	// Jitter in [backoff/2, backoff] spreads out clients retrying together
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// parseRetryAfter parses a Retry-After header given as seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// isTransientError reports whether a request error is worth retrying:
+// timeouts, refused or reset connections and connections closed mid-response
+func isTransientError(err error) bool {
+	if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var fnErr *qurlhttp.LambdaFunctionError
+	if stderrors.As(err, &fnErr) {
+		return false
+	}
+
+	if stderrors.Is(err, syscall.ECONNRESET) || stderrors.Is(err, syscall.ECONNREFUSED) ||
+		stderrors.Is(err, io.ErrUnexpectedEOF) || stderrors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return stderrors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleepContext waits for d, returning early with the context's error if it is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discardBody drains and closes a response that is being retried, so its
+// connection can be reused
+func discardBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+}
@@ -0,0 +1,303 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
+	"github.com/rs/zerolog"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.RetryConfig
+		retried  []int
+		excluded []int
+		wantErr  bool
+	}{
+		{
+			name:     "default status codes",
+			cfg:      config.RetryConfig{Retries: 3},
+			retried:  []int{408, 429, 500, 502, 503, 504},
+			excluded: []int{400, 404, 501},
+		},
+		{
+			name:     "custom status codes",
+			cfg:      config.RetryConfig{Retries: 3, On: []string{"429", " 503"}},
+			retried:  []int{429, 503},
+			excluded: []int{500, 502},
+		},
+		{name: "invalid status code", cfg: config.RetryConfig{On: []string{"5xx"}}, wantErr: true},
+		{name: "out of range status code", cfg: config.RetryConfig{On: []string{"999"}}, wantErr: true},
+		{name: "negative retries", cfg: config.RetryConfig{Retries: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := newRetryPolicy(tt.cfg)
+			if tt.wantErr {
+				if !errors.IsType(err, errors.ErrorTypeValidation) {
+					t.Fatalf("Expected validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, status := range tt.retried {
+				if !policy.shouldRetry(&http.Response{StatusCode: status}, nil) {
+					t.Errorf("Expected %d to be retried", status)
+				}
+			}
+			for _, status := range tt.excluded {
+				if policy.shouldRetry(&http.Response{StatusCode: status}, nil) {
+					t.Errorf("Expected %d not to be retried", status)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Allows(t *testing.T) {
+	policy := &retryPolicy{maxRetries: 2}
+	for method, expected := range map[string]bool{"GET": true, "PUT": true, "DELETE": true, "POST": false, "PATCH": false} {
+		if got := policy.allows(method); got != expected {
+			t.Errorf("allows(%s) = %v, want %v", method, got, expected)
+		}
+	}
+
+	policy.allMethods = true
+	if !policy.allows("POST") {
+		t.Error("Expected POST to be retried with --retry-all-methods")
+	}
+
+	if (&retryPolicy{}).allows("GET") {
+		t.Error("Expected no retries without --retry")
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := &retryPolicy{}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for attempt, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		for i := 0; i < 20; i++ {
+			if d := policy.delay(attempt, nil, now); d < backoff/2 || d > backoff {
+				t.Fatalf("delay(%d) = %v, want within [%v, %v]", attempt, d, backoff/2, backoff)
+			}
+		}
+	}
+
+	if d := policy.delay(40, nil, now); d > retryMaxDelay {
+		t.Errorf("delay(40) = %v, want at most %v", d, retryMaxDelay)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	if d := policy.delay(0, resp, now); d != 7*time.Second {
+		t.Errorf("delay with Retry-After: 7 = %v", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "120", expected: 2 * time.Minute, ok: true},
+		{value: "0", expected: 0, ok: true},
+		{value: "Wed, 01 Jan 2025 12:00:30 GMT", expected: 30 * time.Second, ok: true},
+		{value: "Wed, 01 Jan 2025 11:00:00 GMT", expected: 0, ok: true},
+		{value: "", ok: false},
+		{value: "-5", ok: false},
+		{value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		d, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.ok || d != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, d, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{io.ErrUnexpectedEOF, true},
+		{context.Canceled, false},
+		{&qurlhttp.LambdaFunctionError{Kind: "Unhandled"}, false},
+		{fmt.Errorf("unsupported protocol scheme"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isTransientError(tt.err); got != tt.expected {
+			t.Errorf("isTransientError(%v) = %v, want %v", tt.err, got, tt.expected)
+		}
+	}
+}
+
+// sequenceHTTPClient returns its outcomes in order, recording each request body
+type sequenceHTTPClient struct {
+	outcomes []func() (*http.Response, error)
+	bodies   []string
+}
+
+func (c *sequenceHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+	c.bodies = append(c.bodies, body)
+
+	outcome := c.outcomes[min(len(c.bodies), len(c.outcomes))-1]
+	return outcome()
+}
+
+func respondWith(status int, header http.Header) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader("body"))}, nil
+	}
+}
+
+func failWith(err error) func() (*http.Response, error) {
+	return func() (*http.Response, error) { return nil, err }
+}
+
+func TestExecutor_Retry(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.Config
+		outcomes   []func() (*http.Response, error)
+		attempts   int
+		delays     []time.Duration
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name:       "retries until success",
+			cfg:        config.Config{Methods: []string{"GET"}, Retry: config.RetryConfig{Retries: 3}},
+			outcomes:   []func() (*http.Response, error){respondWith(503, nil), failWith(syscall.ECONNRESET), respondWith(200, nil)},
+			attempts:   3,
+			wantStatus: 200,
+		},
+		{
+			name:       "gives up after the retry count",
+			cfg:        config.Config{Methods: []string{"GET"}, Retry: config.RetryConfig{Retries: 2}},
+			outcomes:   []func() (*http.Response, error){respondWith(503, nil)},
+			attempts:   3,
+			wantStatus: 503,
+		},
+		{
+			name:       "honours Retry-After",
+			cfg:        config.Config{Methods: []string{"GET"}, Retry: config.RetryConfig{Retries: 1}},
+			outcomes:   []func() (*http.Response, error){respondWith(429, http.Header{"Retry-After": []string{"3"}}), respondWith(200, nil)},
+			attempts:   2,
+			delays:     []time.Duration{3 * time.Second},
+			wantStatus: 200,
+		},
+		{
+			name:       "stops when Retry-After exceeds the max time",
+			cfg:        config.Config{Methods: []string{"GET"}, Retry: config.RetryConfig{Retries: 3, MaxTime: 10 * time.Second}},
+			outcomes:   []func() (*http.Response, error){respondWith(429, http.Header{"Retry-After": []string{"60"}})},
+			attempts:   1,
+			wantStatus: 429,
+		},
+		{
+			name:       "does not retry POST by default",
+			cfg:        config.Config{Methods: []string{"POST"}, Data: "payload", Retry: config.RetryConfig{Retries: 3}},
+			outcomes:   []func() (*http.Response, error){respondWith(503, nil), respondWith(200, nil)},
+			attempts:   1,
+			wantStatus: 503,
+		},
+		{
+			name:       "retries POST with --retry-all-methods and replays the body",
+			cfg:        config.Config{Methods: []string{"POST"}, Data: "payload", Retry: config.RetryConfig{Retries: 3, AllMethods: true}},
+			outcomes:   []func() (*http.Response, error){respondWith(503, nil), respondWith(200, nil)},
+			attempts:   2,
+			wantStatus: 200,
+		},
+		{
+			name:       "does not retry other status codes",
+			cfg:        config.Config{Methods: []string{"GET"}, Retry: config.RetryConfig{Retries: 3, On: []string{"429"}}},
+			outcomes:   []func() (*http.Response, error){respondWith(503, nil)},
+			attempts:   1,
+			wantStatus: 503,
+		},
+		{
+			name:     "returns the last error",
+			cfg:      config.Config{Methods: []string{"GET"}, Retry: config.RetryConfig{Retries: 1}},
+			outcomes: []func() (*http.Response, error){failWith(syscall.ECONNREFUSED)},
+			attempts: 2,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &sequenceHTTPClient{outcomes: tt.outcomes}
+			exec := NewExecutorWithDependencies(zerolog.Nop(), client, nil,
+				&mockURLResolver{url: "https://api.example.com/items"}, &mockResponseHandler{}, &tt.cfg).(*executor)
+
+			var delays []time.Duration
+			exec.sleep = func(ctx context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+
+			resp, _, err := exec.executeRequest(context.Background(), "/items")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("executeRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if resp != nil && resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if len(client.bodies) != tt.attempts {
+				t.Errorf("attempts = %d, want %d", len(client.bodies), tt.attempts)
+			}
+			for i, body := range client.bodies {
+				if body != tt.cfg.Data {
+					t.Errorf("attempt %d body = %q, want %q", i+1, body, tt.cfg.Data)
+				}
+			}
+			if tt.delays != nil && fmt.Sprint(delays) != fmt.Sprint(tt.delays) {
+				t.Errorf("delays = %v, want %v", delays, tt.delays)
+			}
+		})
+	}
+}
+
+func TestExecutor_RetryCancelled(t *testing.T) {
+	cfg := &config.Config{Methods: []string{"GET"}, Retry: config.RetryConfig{Retries: 3}}
+	client := &sequenceHTTPClient{outcomes: []func() (*http.Response, error){respondWith(503, nil)}}
+	exec := NewExecutorWithDependencies(zerolog.Nop(), client, nil,
+		&mockURLResolver{url: "https://api.example.com/items"}, &mockResponseHandler{}, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := exec.Execute(ctx, "/items"); err == nil {
+		t.Fatal("Expected an error when cancelled while waiting to retry")
+	}
+	if len(client.bodies) != 1 {
+		t.Errorf("attempts = %d, want 1", len(client.bodies))
+	}
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brendan.keane/qurl/pkg/openapi"
+)
+
+// matchServerName finds the servers whose description best matches a name given
+// to --server. Matching is case-insensitive and tries, in order: an exact
+// description, a description prefix, a substring of the description or URL, and
+// finally the closest description by edit distance. It returns the indices of
+// the servers matched at the first stage that matches any.
+func matchServerName(servers []openapi.ServerInfo, name string) []int {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil
+	}
+
+	stages := []func(server openapi.ServerInfo) bool{
+		func(server openapi.ServerInfo) bool {
+			return strings.ToLower(server.Description) == name
+		},
+		func(server openapi.ServerInfo) bool {
+			return strings.HasPrefix(strings.ToLower(server.Description), name)
+		},
+		func(server openapi.ServerInfo) bool {
+			return strings.Contains(strings.ToLower(server.Description), name) ||
+				strings.Contains(strings.ToLower(server.URL), name)
+		},
+	}
+
+	for _, matches := range stages {
+		var indices []int
+		for i, server := range servers {
+			if server.URL != "" && matches(server) {
+				indices = append(indices, i)
+			}
+		}
+		if len(indices) > 0 {
+			return indices
+		}
+	}
+
+	// Fall back to typo tolerance: accept the closest descriptions within a small distance
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	best := -1
+	var indices []int
+	for i, server := range servers {
+		if server.URL == "" || server.Description == "" {
+			continue
+		}
+		distance := levenshtein(strings.ToLower(server.Description), name)
+		if distance > maxDistance {
+			continue
+		}
+		if best == -1 || distance < best {
+			best = distance
+			indices = []int{i}
+		} else if distance == best {
+			indices = append(indices, i)
+		}
+	}
+
+	return indices
+}
+
+// describeServers lists servers as "index: URL (description)" for error messages
+func describeServers(servers []openapi.ServerInfo) []string {
+	descriptions := make([]string, len(servers))
+	for i, server := range servers {
+		descriptions[i] = describeServer(i, server)
+	}
+	return descriptions
+}
+
+func describeServer(index int, server openapi.ServerInfo) string {
+	if server.Description == "" {
+		return fmt.Sprintf("%d: %s", index, server.URL)
+	}
+	return fmt.Sprintf("%d: %s (%s)", index, server.URL, server.Description)
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
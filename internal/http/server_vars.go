@@ -0,0 +1,22 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+)
+
+// ParseServerVariables parses name=value pairs from the --server-var flag
+func ParseServerVariables(serverVars []string) (map[string]string, error) {
+	vars := make(map[string]string, len(serverVars))
+	for _, serverVar := range serverVars {
+		parts := strings.SplitN(serverVar, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.New(errors.ErrorTypeValidation, "invalid server variable").
+				WithContext("server_var", serverVar).
+				WithContext("suggestion", "use the format name=value (e.g., --server-var region=eu)")
+		}
+		vars[strings.TrimSpace(parts[0])] = parts[1]
+	}
+	return vars, nil
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/brendan.keane/qurl/internal/errors"
+)
+
+func TestParseServerVariables(t *testing.T) {
+	vars, err := ParseServerVariables([]string{"region=eu", " basePath =v2=beta"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vars["region"] != "eu" || vars["basePath"] != "v2=beta" {
+		t.Errorf("Unexpected variables: %v", vars)
+	}
+
+	for _, invalid := range []string{"region", "=eu"} {
+		if _, err := ParseServerVariables([]string{invalid}); !errors.IsType(err, errors.ErrorTypeValidation) {
+			t.Errorf("Expected validation error for %q, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSSERetry is the reconnection delay used until the server sends a retry field
+const defaultSSERetry = 3 * time.Second
+
+// EventStreamState carries a Server-Sent Events stream across reconnects
+type EventStreamState struct {
+	LastEventID string        // id of the last event, sent as Last-Event-ID when reconnecting
+	Retry       time.Duration // Reconnection delay, set by the server's retry field
+	Reconnects  int           // Number of reconnects so far
+	Events      int           // Number of events received so far
+}
+
+// sseEvent is one dispatched Server-Sent Event
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// isEventStream reports whether a response is a Server-Sent Events stream
+func isEventStream(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// sseReader parses a text/event-stream body as described in the HTML
+// specification: events are separated by blank lines, data lines are joined
+// with newlines and lines starting with a colon are comments
+type sseReader struct {
+	r      *bufio.Reader
+	lastID string        // Last event ID seen, including on events without data
+	retry  time.Duration // Last retry field seen, zero if none
+	first  bool
+}
+
+func newSSEReader(r io.Reader, lastID string) *sseReader {
+	return &sseReader{r: bufio.NewReader(r), lastID: lastID, first: true}
+}
+
+// Next returns the next event. It returns io.EOF when the stream ends; an
+// event that is not terminated by a blank line is discarded.
+func (s *sseReader) Next() (*sseEvent, error) {
+	var data strings.Builder
+	var eventType string
+
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if s.first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			s.first = false
+		}
+
+		if line == "" {
+			if data.Len() == 0 {
+				// Nothing to dispatch; an id or retry field still applies
+				eventType = ""
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+			return &sseEvent{
+				ID:    s.lastID,
+				Event: eventType,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+			}, nil
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue // Comment, often sent as a keep-alive
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "event":
+			eventType = value
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 32); err == nil {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// writeEvent prints an event: its data followed by a newline, or with ndjson
+// one JSON object per line. JSON data is embedded as is; other data as a string.
+func writeEvent(w io.Writer, event *sseEvent, ndjson bool) error {
+	if !ndjson {
+		_, err := fmt.Fprintln(w, event.Data)
+		return err
+	}
+
+	record := struct {
+		ID    string      `json:"id,omitempty"`
+		Event string      `json:"event"`
+		Data  interface{} `json:"data"`
+	}{ID: event.ID, Event: event.Event, Data: event.Data}
+	if json.Valid([]byte(event.Data)) {
+		record.Data = json.RawMessage(event.Data)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", line)
+	return err
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEReader(t *testing.T) {
+	tests := []struct {
+		name       string
+		stream     string
+		lastID     string
+		want       []sseEvent
+		wantLastID string
+		wantRetry  time.Duration
+	}{
+		{
+			name:   "single event",
+			stream: "data: hello\n\n",
+			want:   []sseEvent{{Event: "message", Data: "hello"}},
+		},
+		{
+			name:   "multi-line data and event type",
+			stream: "event: update\ndata: line 1\ndata: line 2\n\n",
+			want:   []sseEvent{{Event: "update", Data: "line 1\nline 2"}},
+		},
+		{
+			name:       "ids carry over to later events",
+			stream:     "id: 1\ndata: a\n\ndata: b\n\nid: 3\n\n",
+			want:       []sseEvent{{ID: "1", Event: "message", Data: "a"}, {ID: "1", Event: "message", Data: "b"}},
+			wantLastID: "3",
+		},
+		{
+			name:       "resumed stream starts from the given id",
+			stream:     "data: c\n\n",
+			lastID:     "2",
+			want:       []sseEvent{{ID: "2", Event: "message", Data: "c"}},
+			wantLastID: "2",
+		},
+		{
+			name:   "comments, CRLF and a byte order mark",
+			stream: "\ufeff: keep-alive\r\ndata:no space\r\n\r\n",
+			want:   []sseEvent{{Event: "message", Data: "no space"}},
+		},
+		{
+			name:      "retry field",
+			stream:    "retry: 1500\n\nretry: soon\ndata: x\n\n",
+			want:      []sseEvent{{Event: "message", Data: "x"}},
+			wantRetry: 1500 * time.Millisecond,
+		},
+		{
+			name:   "unterminated event is discarded",
+			stream: "data: complete\n\ndata: partial",
+			want:   []sseEvent{{Event: "message", Data: "complete"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newSSEReader(strings.NewReader(tt.stream), tt.lastID)
+
+			var got []sseEvent
+			for {
+				event, err := reader.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				got = append(got, *event)
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantLastID, reader.lastID)
+			assert.Equal(t, tt.wantRetry, reader.retry)
+		})
+	}
+}
+
+func TestWriteEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		event  sseEvent
+		ndjson bool
+		want   string
+	}{
+		{"text", sseEvent{ID: "1", Event: "message", Data: "hello"}, false, "hello\n"},
+		{"ndjson with JSON data", sseEvent{ID: "1", Event: "update", Data: `{"n":1}`}, true, `{"id":"1","event":"update","data":{"n":1}}` + "\n"},
+		{"ndjson with text data", sseEvent{Event: "message", Data: "line 1\nline 2"}, true, `{"event":"message","data":"line 1\nline 2"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeEvent(&buf, &tt.event, tt.ndjson))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestIsEventStream(t *testing.T) {
+	for contentType, want := range map[string]bool{
+		"text/event-stream":                true,
+		"text/event-stream; charset=utf-8": true,
+		"application/json":                 false,
+		"":                                 false,
+	} {
+		resp := &http.Response{Header: http.Header{"Content-Type": []string{contentType}}}
+		assert.Equal(t, want, isEventStream(resp), contentType)
+	}
+}
+
+func TestExecuteEventStreamReconnect(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		connection := len(lastEventIDs)
+		mu.Unlock()
+
+		switch connection {
+		case 1:
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "retry: 10\n\nid: 1\ndata: {\"n\":1}\n\nid: 2\ndata: {\"n\":2}\n\n")
+		case 2:
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "id: 3\nevent: done\ndata: {\"n\":3}\n\n")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Methods: []string{"GET"},
+		Server:  server.URL,
+		SSE:     config.SSEConfig{NDJSON: true, LastEventID: "0", Reconnect: true},
+	}
+	exec, err := NewClientFactory(zerolog.Nop()).CreateExecutor(cfg)
+	require.NoError(t, err)
+
+	var delays []time.Duration
+	exec.(*executor).sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	output := captureStdout(t, func() {
+		require.NoError(t, exec.Execute(context.Background(), "/events"))
+	})
+
+	assert.Equal(t, `{"id":"1","event":"message","data":{"n":1}}
+{"id":"2","event":"message","data":{"n":2}}
+{"id":"3","event":"done","data":{"n":3}}
+`, output)
+	assert.Equal(t, []string{"0", "2", "3"}, lastEventIDs)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 10 * time.Millisecond}, delays)
+}
+
+func TestExecuteEventStreamWithoutReconnect(t *testing.T) {
+	connections := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: one\n\ndata: two\n\n")
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Methods: []string{"GET"}, Server: server.URL}
+	exec, err := NewClientFactory(zerolog.Nop()).CreateExecutor(cfg)
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, exec.Execute(context.Background(), "/events"))
+	})
+
+	assert.Equal(t, "one\ntwo\n", output)
+	assert.Equal(t, 1, connections)
+}
+
+// captureStdout returns what fn writes to os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	original := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = original }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
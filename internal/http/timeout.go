@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
+)
+
+// newHTTPClient creates the Lambda-capable client for cfg. With --connect-timeout
+// set, connections are dialed through a copy of the default transport that
+// gives up on establishing a connection after that long.
+func newHTTPClient(cfg *config.Config) (*qurlhttp.Client, error) {
+	if cfg.Timeouts.Connect <= 0 {
+		return qurlhttp.NewClient()
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   cfg.Timeouts.Connect,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = cfg.Timeouts.Connect
+
+	return qurlhttp.NewClientWithHTTPClient(&http.Client{Transport: transport})
+}
+
+// interruptedError describes a request stopped by its context, either by
+// --max-time or by Ctrl-C, or a connection that hit --connect-timeout.
+// It returns nil for other errors.
+func interruptedError(err error, targetURL string) *errors.QUrlError {
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return errors.Wrap(err, errors.ErrorTypeNetwork, "request timed out").
+			WithContext("url", targetURL).
+			WithContext("suggestion", "increase the time limit with --max-time")
+	case stderrors.Is(err, context.Canceled):
+		return errors.Wrap(err, errors.ErrorTypeNetwork, "request cancelled").
+			WithContext("url", targetURL)
+	}
+
+	var opErr *net.OpError
+	if stderrors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout() {
+		return errors.Wrap(err, errors.ErrorTypeNetwork, "timed out connecting to server").
+			WithContext("url", targetURL).
+			WithContext("suggestion", "increase the connection time limit with --connect-timeout")
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowServer answers only once the client gives up on the request
+func slowServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExecuteRequestStopsWithContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         func() (context.Context, context.CancelFunc)
+		wantMessage string
+	}{
+		{
+			name: "max time exceeded",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantMessage: "request timed out",
+		},
+		{
+			name: "interrupted",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantMessage: "request cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := slowServer(t)
+			cfg := &config.Config{Methods: []string{"GET"}, Server: server.URL}
+
+			exec, err := NewClientFactory(zerolog.Nop()).CreateExecutor(cfg)
+			require.NoError(t, err)
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			start := time.Now()
+			_, _, err = exec.(*executor).executeRequest(ctx, "/report")
+			require.Error(t, err)
+			assert.Less(t, time.Since(start), 5*time.Second)
+
+			var qErr *errors.QUrlError
+			require.True(t, stderrors.As(err, &qErr))
+			assert.Equal(t, errors.ErrorTypeNetwork, qErr.Type)
+			assert.Equal(t, tt.wantMessage, qErr.Message)
+		})
+	}
+}
+
+func TestInterruptedError(t *testing.T) {
+	dialTimeout := &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}
+
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+	}{
+		{"deadline", fmt.Errorf("Get: %w", context.DeadlineExceeded), "request timed out"},
+		{"cancelled", fmt.Errorf("Get: %w", context.Canceled), "request cancelled"},
+		{"connect timeout", fmt.Errorf("Get: %w", dialTimeout), "timed out connecting to server"},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}, ""},
+		{"other", fmt.Errorf("boom"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qErr := interruptedError(tt.err, "https://api.example.com")
+			if tt.wantMessage == "" {
+				assert.Nil(t, qErr)
+				return
+			}
+			require.NotNil(t, qErr)
+			assert.Equal(t, tt.wantMessage, qErr.Message)
+			assert.Equal(t, "https://api.example.com", qErr.Context["url"])
+		})
+	}
+}
+
+func TestNewHTTPClientConnectTimeout(t *testing.T) {
+	client, err := newHTTPClient(&config.Config{})
+	require.NoError(t, err)
+	assert.Same(t, http.DefaultClient, client.Client)
+
+	client, err = newHTTPClient(&config.Config{Timeouts: config.TimeoutConfig{Connect: 3 * time.Second}})
+	require.NoError(t, err)
+	require.NotSame(t, http.DefaultClient, client.Client)
+
+	transport, ok := client.Client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, transport.TLSHandshakeTimeout)
+	assert.NotNil(t, transport.DialContext)
+}
+
+// timeoutError is a net.Error that reports a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -2,12 +2,14 @@ package http
 
 import (
 	"context"
+	"net"
 	"net/url"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/brendan.keane/qurl/internal/config"
 	"github.com/brendan.keane/qurl/internal/errors"
+	qurlhttp "github.com/brendan.keane/qurl/pkg/http"
 )
 
 // urlResolver implements URLResolver interface
@@ -28,6 +30,15 @@ func NewURLResolver(config *config.Config, openapi OpenAPIProvider) URLResolver
 // ResolveURL determines the final URL for the request
 // This consolidates the complex URL resolution logic that was previously scattered
 func (r *urlResolver) ResolveURL(ctx context.Context, path string) (string, error) {
+	// Expand {name} placeholders from --path-param values
+	path, err := ApplyPathParameters(path, r.config.PathParams)
+	if err != nil {
+		return "", err
+	}
+
+	// Lambda ARNs and name:qualifier targets need rewriting before url.Parse
+	path = qurlhttp.NormalizeLambdaURL(path)
+
 	// Parse the path to check if it's an absolute URL
 	parsedURL, err := url.Parse(path)
 	if err != nil {
@@ -45,7 +56,7 @@ func (r *urlResolver) ResolveURL(ctx context.Context, path string) (string, erro
 
 	// Priority 1: --server flag provided
 	if r.config.Server != "" {
-		baseURL, err = r.resolveServerURL(r.config.Server)
+		baseURL, err = r.resolveServerURL(ctx, r.config.Server)
 		if err != nil {
 			return "", err
 		}
@@ -65,7 +76,7 @@ func (r *urlResolver) ResolveURL(ctx context.Context, path string) (string, erro
 	}
 
 	// Parse the base URL
-	base, err := url.Parse(baseURL)
+	base, err := url.Parse(qurlhttp.NormalizeLambdaURL(baseURL))
 	if err != nil {
 		return "", errors.Wrap(err, errors.ErrorTypeValidation, "invalid base URL").
 			WithContext("base_url", baseURL)
@@ -86,31 +97,100 @@ func (r *urlResolver) ResolveURL(ctx context.Context, path string) (string, erro
 	return base.String(), nil
 }
 
-// resolveServerURL resolves the server URL based on the --server flag value
-func (r *urlResolver) resolveServerURL(serverFlag string) (string, error) {
-	// Check if it's a numeric index (0, 1, 2, etc.)
-	if len(serverFlag) == 1 && serverFlag[0] >= '0' && serverFlag[0] <= '9' {
-		// Parse as server index
-		index := int(serverFlag[0] - '0')
+// isSchemelessHost reports whether a --server value is a host given without a
+// scheme: it contains a dot, colon or slash, but is neither a URL nor a path
+func isSchemelessHost(server string) bool {
+	return !strings.Contains(server, "://") &&
+		!strings.HasPrefix(server, "/") &&
+		!strings.ContainsAny(server, " \t") &&
+		strings.ContainsAny(server, ".:/")
+}
+
+// defaultScheme picks the scheme for a host given without one: http for the
+// local machine, where servers rarely have certificates, and https otherwise
+func defaultScheme(host string) string {
+	parsed, err := url.Parse("//" + host)
+	if err != nil {
+		return "https"
+	}
+	hostname := parsed.Hostname()
+	if hostname == "localhost" {
+		return "http"
+	}
+	if ip := net.ParseIP(hostname); ip != nil && ip.IsLoopback() {
+		return "http"
+	}
+	return "https"
+}
+
+// SpecURL returns the --openapi URL in a form net/url can parse, rewriting
+// lambda:// ARNs and qualifiers
+func SpecURL(cfg *config.Config) string {
+	return qurlhttp.NormalizeLambdaURL(cfg.OpenAPIURL)
+}
+
+// resolveServerURL resolves the server URL based on the --server flag value.
+// The value may be a full URL, a host without a scheme, an index into the
+// spec's servers, or a name matched against the server descriptions.
+func (r *urlResolver) resolveServerURL(ctx context.Context, serverFlag string) (string, error) {
+	// A host such as api.example.com or localhost:8080 is not a server name
+	if isSchemelessHost(serverFlag) {
+		serverFlag = defaultScheme(serverFlag) + "://" + serverFlag
+	}
 
+	// Check if it's a numeric index (0, 1, ..., 12, ...)
+	if index, err := strconv.Atoi(serverFlag); err == nil {
 		if r.openapi == nil {
 			return "", errors.New(errors.ErrorTypeConfig, "server index requires OpenAPI specification").
 				WithContext("index", index)
 		}
 
 		// Try to get servers from OpenAPI viewer
-		servers, err := r.openapi.GetServers()
+		servers, err := r.openapi.GetServers(ctx)
 		if err != nil {
 			return "", errors.Wrap(err, errors.ErrorTypeOpenAPI, "failed to get servers from OpenAPI spec")
 		}
 
-		if index >= len(servers) {
+		if index < 0 || index >= len(servers) {
 			return "", errors.New(errors.ErrorTypeValidation, "server index out of range").
 				WithContext("index", index).
-				WithContext("available_servers", len(servers))
+				WithContext("available_servers", describeServers(servers))
+		}
+
+		return servers[index].URL, nil
+	}
+
+	// Anything that isn't a URL or a path names a server by its description
+	if !strings.Contains(serverFlag, "://") && !strings.HasPrefix(serverFlag, "/") {
+		if r.openapi == nil {
+			return "", errors.New(errors.ErrorTypeConfig, "server name requires OpenAPI specification").
+				WithContext("server", serverFlag)
 		}
 
-		return servers[index], nil
+		servers, err := r.openapi.GetServers(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, errors.ErrorTypeOpenAPI, "failed to get servers from OpenAPI spec")
+		}
+
+		matches := matchServerName(servers, serverFlag)
+		switch len(matches) {
+		case 1:
+			return servers[matches[0]].URL, nil
+		case 0:
+			return "", errors.New(errors.ErrorTypeValidation, "no server matches name").
+				WithContext("server", serverFlag).
+				WithContext("available_servers", describeServers(servers)).
+				WithContext("suggestion", "use a server index, a description or a full URL")
+		default:
+			candidates := make([]string, len(matches))
+			for i, index := range matches {
+				candidates[i] = describeServer(index, servers[index])
+			}
+			return "", errors.New(errors.ErrorTypeValidation, "server name is ambiguous").
+				WithContext("server", serverFlag).
+				WithContext("candidates", candidates).
+				WithContext("suggestion", "use a more specific name or the server index")
+		}
 	}
 
 	// Check if it looks like a relative URL that needs OpenAPI resolution
@@ -121,8 +201,6 @@ func (r *urlResolver) resolveServerURL(serverFlag string) (string, error) {
 				return "", errors.New(errors.ErrorTypeConfig, "relative server URL requires OpenAPI specification")
 			}
 			// Use the viewer's logic to resolve relative URLs
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
 			return r.openapi.BaseURL(ctx)
 		}
 
@@ -130,6 +208,7 @@ func (r *urlResolver) resolveServerURL(serverFlag string) (string, error) {
 	}
 
 	// Treat as full URL
+	serverFlag = qurlhttp.NormalizeLambdaURL(serverFlag)
 	parsedURL, err := url.Parse(serverFlag)
 	if err != nil {
 		return "", errors.Wrap(err, errors.ErrorTypeValidation, "invalid server URL").
@@ -2,9 +2,16 @@ package http
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/pkg/openapi"
 )
 
 func TestURLResolver_ResolveURL(t *testing.T) {
@@ -46,6 +53,18 @@ func TestURLResolver_ResolveURL(t *testing.T) {
 			path:   "/users",
 			expected: "https://api.example.com/users",
 		},
+		{
+			name:     "Lambda ARN server with qualifier",
+			config:   &config.Config{Server: "lambda://arn:aws:lambda:eu-west-1:123456789012:function:my-fn:prod"},
+			path:     "/users",
+			expected: "lambda://arn%3Aaws%3Alambda%3Aeu-west-1%3A123456789012%3Afunction%3Amy-fn@prod/users",
+		},
+		{
+			name:     "absolute Lambda URL with alias",
+			config:   &config.Config{},
+			path:     "lambda://my-fn:prod/users",
+			expected: "lambda://my-fn@prod/users",
+		},
 		{
 			name: "openapi base URL fallback",
 			config: &config.Config{},
@@ -113,7 +132,7 @@ func TestURLResolver_ServerIndex(t *testing.T) {
 			name:     "server index 0",
 			config:   &config.Config{Server: "0"},
 			openapi:  &mockOpenAPIWithServers{
-				mockOpenAPIProvider{servers: []string{"https://api.example.com", "https://staging.example.com"}},
+				mockOpenAPIProvider{servers: []openapi.ServerInfo{{URL: "https://api.example.com"}, {URL: "https://staging.example.com"}}},
 			},
 			path:     "/users",
 			expected: "https://api.example.com/users",
@@ -122,7 +141,7 @@ func TestURLResolver_ServerIndex(t *testing.T) {
 			name:     "server index 1",
 			config:   &config.Config{Server: "1"},
 			openapi:  &mockOpenAPIWithServers{
-				mockOpenAPIProvider{servers: []string{"https://api.example.com", "https://staging.example.com"}},
+				mockOpenAPIProvider{servers: []openapi.ServerInfo{{URL: "https://api.example.com"}, {URL: "https://staging.example.com"}}},
 			},
 			path:     "/users",
 			expected: "https://staging.example.com/users",
@@ -131,7 +150,7 @@ func TestURLResolver_ServerIndex(t *testing.T) {
 			name:      "server index out of range",
 			config:    &config.Config{Server: "2"},
 			openapi:   &mockOpenAPIWithServers{
-				mockOpenAPIProvider{servers: []string{"https://api.example.com"}},
+				mockOpenAPIProvider{servers: []openapi.ServerInfo{{URL: "https://api.example.com"}}},
 			},
 			path:      "/users",
 			wantError: true,
@@ -142,6 +161,86 @@ func TestURLResolver_ServerIndex(t *testing.T) {
 			path:      "/users",
 			wantError: true,
 		},
+		{
+			name:     "multi-digit server index",
+			config:   &config.Config{Server: "12"},
+			openapi:  &mockOpenAPIWithServers{mockOpenAPIProvider{servers: numberedServers(13)}},
+			path:     "/users",
+			expected: "https://server12.example.com/users",
+		},
+		{
+			name:     "server name matches description exactly",
+			config:   &config.Config{Server: "Staging"},
+			openapi:  &mockOpenAPIWithServers{mockOpenAPIProvider{servers: namedServers}},
+			path:     "/users",
+			expected: "https://staging.example.com/users",
+		},
+		{
+			name:     "server name matches description prefix",
+			config:   &config.Config{Server: "prod"},
+			openapi:  &mockOpenAPIWithServers{mockOpenAPIProvider{servers: namedServers}},
+			path:     "/users",
+			expected: "https://api.example.com/users",
+		},
+		{
+			name:     "server name matches URL",
+			config:   &config.Config{Server: "localhost"},
+			openapi:  &mockOpenAPIWithServers{mockOpenAPIProvider{servers: namedServers}},
+			path:     "/users",
+			expected: "http://localhost:8080/users",
+		},
+		{
+			name:     "server name with typo",
+			config:   &config.Config{Server: "stagign"},
+			openapi:  &mockOpenAPIWithServers{mockOpenAPIProvider{servers: namedServers}},
+			path:     "/users",
+			expected: "https://staging.example.com/users",
+		},
+		{
+			name:      "ambiguous server name",
+			config:    &config.Config{Server: "example"},
+			openapi:   &mockOpenAPIWithServers{mockOpenAPIProvider{servers: namedServers}},
+			path:      "/users",
+			wantError: true,
+		},
+		{
+			name:      "unknown server name",
+			config:    &config.Config{Server: "qa"},
+			openapi:   &mockOpenAPIWithServers{mockOpenAPIProvider{servers: namedServers}},
+			path:      "/users",
+			wantError: true,
+		},
+		{
+			name:     "host without scheme",
+			config:   &config.Config{Server: "api.example.com"},
+			openapi:  &mockOpenAPIWithServers{mockOpenAPIProvider{servers: namedServers}},
+			path:     "/users",
+			expected: "https://api.example.com/users",
+		},
+		{
+			name:     "host and base path without scheme",
+			config:   &config.Config{Server: "api.example.com/v1"},
+			path:     "/users",
+			expected: "https://api.example.com/v1/users",
+		},
+		{
+			name:     "localhost port without scheme",
+			config:   &config.Config{Server: "localhost:8080"},
+			path:     "/users",
+			expected: "http://localhost:8080/users",
+		},
+		{
+			name:     "loopback address without scheme",
+			config:   &config.Config{Server: "127.0.0.1:3000"},
+			path:     "/users",
+			expected: "http://127.0.0.1:3000/users",
+		},
+		{
+			name:      "server name without OpenAPI",
+			config:    &config.Config{Server: "staging"},
+			path:      "/users",
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -169,6 +268,72 @@ func TestURLResolver_ServerIndex(t *testing.T) {
 	}
 }
 
+func TestURLResolver_ServerErrorListsServers(t *testing.T) {
+	resolver := NewURLResolver(&config.Config{Server: "qa"}, &mockOpenAPIProvider{servers: namedServers})
+
+	_, err := resolver.ResolveURL(context.Background(), "/users")
+	available, ok := errors.GetContext(err)["available_servers"].([]string)
+	if !ok || len(available) != len(namedServers) {
+		t.Fatalf("Expected available servers in error context, got %v", errors.GetContext(err))
+	}
+	if available[1] != "1: https://staging.example.com (Staging)" {
+		t.Errorf("Unexpected server description %q", available[1])
+	}
+}
+
+func TestURLResolver_ServerLookupIsCancellable(t *testing.T) {
+	// The spec never arrives, so only cancellation ends the lookup
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	for _, server := range []string{"1", "staging"} {
+		t.Run(server, func(t *testing.T) {
+			viewer := openapi.NewViewer(http.DefaultClient, srv.URL+"/openapi.json")
+			resolver := NewURLResolver(&config.Config{Server: server}, NewOpenAPIAdapter(viewer))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := resolver.ResolveURL(ctx, "/users")
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				if err == nil {
+					t.Error("Expected an error when the spec cannot be loaded")
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Server lookup ignored the cancelled context")
+			}
+		})
+	}
+}
+
+var namedServers = []openapi.ServerInfo{
+	{URL: "https://api.example.com", Description: "Production"},
+	{URL: "https://staging.example.com", Description: "Staging"},
+	{URL: "http://localhost:8080", Description: "Local development"},
+}
+
+// numberedServers returns n servers named server0..server(n-1)
+func numberedServers(n int) []openapi.ServerInfo {
+	servers := make([]openapi.ServerInfo, n)
+	for i := range servers {
+		servers[i] = openapi.ServerInfo{URL: fmt.Sprintf("https://server%d.example.com", i)}
+	}
+	return servers
+}
+
 // Mock OpenAPI provider that implements BaseURL method
 type mockOpenAPIWithBaseURL struct {
 	mockOpenAPIProvider
@@ -177,4 +342,19 @@ type mockOpenAPIWithBaseURL struct {
 // Mock OpenAPI provider that implements GetServers method
 type mockOpenAPIWithServers struct {
 	mockOpenAPIProvider
-}
\ No newline at end of file
+}
+func TestSpecURL(t *testing.T) {
+	cfg := &config.Config{OpenAPIURL: "lambda://arn:aws:lambda:us-east-1:123456789012:function:api:live/openapi.json"}
+
+	parsed, err := url.Parse(SpecURL(cfg))
+	if err != nil {
+		t.Fatalf("Spec URL does not parse: %v", err)
+	}
+	if parsed.Path != "/openapi.json" {
+		t.Errorf("Expected path /openapi.json, got %q", parsed.Path)
+	}
+
+	if got := SpecURL(&config.Config{OpenAPIURL: "https://api.example.com/openapi.json"}); got != "https://api.example.com/openapi.json" {
+		t.Errorf("Expected other URLs unchanged, got %q", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/brendan.keane/qurl/internal/config"
 	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/internal/filter"
 	"github.com/brendan.keane/qurl/internal/http"
 	"github.com/brendan.keane/qurl/pkg/openapi"
 	"github.com/rs/zerolog"
@@ -26,16 +27,16 @@ const (
   - Required and optional parameters
   - Request body schemas
   - Response body structure and JSON keys
-  - This helps you craft targeted jmespath or regex filters to extract only the data you need
+  - This helps you craft targeted filters to extract only the data you need
 
 **Why this matters:**
 Understanding the response structure lets you use filters effectively, saving tokens by requesting only relevant data instead of the full response.`
 
-	executeToolDescription = `Make HTTP requests to API endpoints. IMPORTANT: Always use filters (jmespath or regex) when you only need specific data from the response.
+	executeToolDescription = `Make HTTP requests to API endpoints. IMPORTANT: Always use filters (a JSON query language or regex) when you only need specific data from the response.
 
 **Best practices:**
 1. Use 'discover' tool first to understand the response structure
-2. Use 'jmespath' filter for JSON responses when you only need specific fields (saves tokens!)
+2. Use a JSON query language filter for JSON responses when you only need specific fields (saves tokens!) - pick whichever language you know best
 3. Use 'regex' filter to search for specific patterns in any response type
 4. Only request unfiltered responses when you need the complete data
 
@@ -47,39 +48,30 @@ Provide a specific path (e.g., /users/123) to see complete documentation includi
 - Request parameters (query, path, header)
 - Request body schemas with field descriptions
 - Response body structure showing all available JSON keys
-- This information is ESSENTIAL for crafting effective jmespath or regex filters`
+- This information is ESSENTIAL for crafting effective filters`
 
 	discoverMethodParamDescription = `HTTP method filter (GET, POST, PUT, DELETE, etc.).
 
 Specify a method to see detailed schemas for that specific operation. Use 'ANY' or omit to see all available methods for the path.`
 
-	executePathParamDescription      = `API endpoint path (required)`
-	executeMethodParamDescription    = `HTTP method (GET, POST, PUT, DELETE, etc.)`
-	executeHeadersParamDescription   = `HTTP headers as key-value pairs`
-	executeQueryParamDescription     = `Query parameters as key-value pairs`
-	executeBodyParamDescription      = `Request body data`
-	executeRegexParamDescription     = `Regex pattern to search response text (returns matches with surrounding context).
+	executePathParamDescription    = `API endpoint path (required)`
+	executeMethodParamDescription  = `HTTP method (GET, POST, PUT, DELETE, etc.)`
+	executeHeadersParamDescription = `HTTP headers as key-value pairs`
+	executeQueryParamDescription   = `Query parameters as key-value pairs`
+	executeBodyParamDescription    = `Request body data`
+	executeRegexParamDescription   = `Regex pattern to search response text (returns matches with surrounding context).
 
-Works with any text format including minified JSON. Use this when searching for specific patterns or terms. Cannot be used with jmespath.
+Works with any text format including minified JSON. Use this when searching for specific patterns or terms. Cannot be used with a JSON query language filter.
 
 **When to use:** Searching for specific strings, patterns, or when you don't know the exact JSON structure.`
 
-	executeJMESPathParamDescription = `JMESPath expression to filter JSON response (https://jmespath.org).
-
-**STRONGLY RECOMMENDED** when working with JSON responses and you only need specific fields. This dramatically reduces token usage.
-
-Cannot be used with regex.
-
-**Examples:**
-- 'items[].name' - extract just the name field from an array
-- 'data.{id: id, name: name}' - extract only id and name fields
-- 'items[?price > 100]' - filter items by condition
-
-**When to use:** Always prefer this for JSON responses when you need specific fields rather than the entire response.`
-
 	executeContextLinesParamDescription = `Amount of context to show around regex matches. Multiplied by ~80 characters per 'line' (default: 5 = ~400 chars of context).
 
 Only used with regex parameter. Increase for more context, decrease for more precise matches.`
+
+	executeValidateParamDescription = `Validate the request against the OpenAPI spec before sending it (default: false).
+
+Rejected requests return the list of violations. The path must match an operation in the spec.`
 )
 
 // Server implements the MCP server protocol
@@ -88,6 +80,7 @@ type Server struct {
 	config     *config.Config
 	executor   http.HTTPExecutor
 	viewer     *openapi.Viewer
+	ctx        context.Context // Cancelled when the server is interrupted
 }
 
 // MCPRequest represents an incoming MCP request
@@ -114,6 +107,11 @@ type MCPError struct {
 
 // NewServer creates a new MCP server
 func NewServer(logger zerolog.Logger, cfg *config.Config) (*Server, error) {
+	serverVars, err := http.ParseServerVariables(cfg.ServerVars)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize HTTP client factory
 	factory := http.NewClientFactory(logger)
 
@@ -125,23 +123,50 @@ func NewServer(logger zerolog.Logger, cfg *config.Config) (*Server, error) {
 
 	// Create OpenAPI viewer with authenticated HTTP client
 	authClient := http.NewAuthenticatedHTTPClient(cfg, logger)
-	viewer := openapi.NewViewer(authClient, cfg.OpenAPIURL)
+	viewer := openapi.NewViewer(authClient, http.SpecURL(cfg))
+	viewer.SetSpecTimeout(cfg.Timeouts.Spec)
+	viewer.SetServerVariables(serverVars)
 
 	return &Server{
 		logger:     logger.With().Str("component", "mcp_server").Logger(),
 		config:     cfg,
 		executor:   executor,
 		viewer:     viewer,
+		ctx:        context.Background(),
 	}, nil
 }
 
-// Start begins the MCP server message loop
-func (s *Server) Start() error {
+// Start begins the MCP server message loop. It returns when stdin is closed
+// or ctx is cancelled; cancelling ctx also aborts the request in flight.
+func (s *Server) Start(ctx context.Context) error {
 	s.logger.Debug().Msg("MCP server started, reading from stdin")
+	s.ctx = ctx
 
 	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		var line string
+		var ok bool
+		select {
+		case <-ctx.Done():
+			s.logger.Debug().Msg("MCP server interrupted")
+			return nil
+		case line, ok = <-lines:
+		}
+		if !ok {
+			break
+		}
 		if strings.TrimSpace(line) == "" {
 			continue
 		}
@@ -163,6 +188,16 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// requestContext returns the context for one tool call, limited by --max-time
+// or, when that is not set, by defaultTimeout
+func (s *Server) requestContext(defaultTimeout time.Duration) (context.Context, context.CancelFunc) {
+	timeout := defaultTimeout
+	if s.config.Timeouts.MaxTime > 0 {
+		timeout = s.config.Timeouts.MaxTime
+	}
+	return context.WithTimeout(s.ctx, timeout)
+}
+
 // handleMessage processes a single MCP message
 func (s *Server) handleMessage(line string) error {
 	var req MCPRequest
@@ -227,6 +262,53 @@ func (s *Server) handleInitialize(id interface{}, params interface{}) error {
 
 // handleToolsList returns the list of available tools
 func (s *Server) handleToolsList(id interface{}) error {
+	executeProperties := map[string]interface{}{
+		"path": map[string]interface{}{
+			"type":        "string",
+			"description": executePathParamDescription,
+		},
+		"method": map[string]interface{}{
+			"type":        "string",
+			"description": executeMethodParamDescription,
+			"default":     "GET",
+		},
+		"headers": map[string]interface{}{
+			"type":        "object",
+			"description": executeHeadersParamDescription,
+		},
+		"query": map[string]interface{}{
+			"type":        "object",
+			"description": executeQueryParamDescription,
+		},
+		"body": map[string]interface{}{
+			"type":        "string",
+			"description": executeBodyParamDescription,
+		},
+		"regex": map[string]interface{}{
+			"type":        "string",
+			"description": executeRegexParamDescription,
+		},
+		"context_lines": map[string]interface{}{
+			"type":        "integer",
+			"description": executeContextLinesParamDescription,
+			"default":     5,
+		},
+		"validate": map[string]interface{}{
+			"type":        "boolean",
+			"description": executeValidateParamDescription,
+			"default":     false,
+		},
+	}
+
+	// Each registered query language is a parameter of its own
+	for _, name := range filter.Languages() {
+		language, _ := filter.Lookup(name)
+		executeProperties[name] = map[string]interface{}{
+			"type":        "string",
+			"description": queryParamDescription(language),
+		}
+	}
+
 	tools := []map[string]interface{}{
 		{
 			"name":        "discover",
@@ -249,44 +331,9 @@ func (s *Server) handleToolsList(id interface{}) error {
 			"name":        "execute",
 			"description": executeToolDescription,
 			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"path": map[string]interface{}{
-						"type":        "string",
-						"description": executePathParamDescription,
-					},
-					"method": map[string]interface{}{
-						"type":        "string",
-						"description": executeMethodParamDescription,
-						"default":     "GET",
-					},
-					"headers": map[string]interface{}{
-						"type":        "object",
-						"description": executeHeadersParamDescription,
-					},
-					"query": map[string]interface{}{
-						"type":        "object",
-						"description": executeQueryParamDescription,
-					},
-					"body": map[string]interface{}{
-						"type":        "string",
-						"description": executeBodyParamDescription,
-					},
-					"regex": map[string]interface{}{
-						"type":        "string",
-						"description": executeRegexParamDescription,
-					},
-					"jmespath": map[string]interface{}{
-						"type":        "string",
-						"description": executeJMESPathParamDescription,
-					},
-					"context_lines": map[string]interface{}{
-						"type":        "integer",
-						"description": executeContextLinesParamDescription,
-						"default":     5,
-					},
-				},
-				"required": []string{"path"},
+				"type":       "object",
+				"properties": executeProperties,
+				"required":   []string{"path"},
 			},
 		},
 	}
@@ -394,7 +441,7 @@ func (s *Server) executeDiscover(id interface{}, args map[string]interface{}) er
 		Str("path_prefix", s.config.MCP.PathPrefix).
 		Msg("discovering endpoints with constraints")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := s.requestContext(10 * time.Second)
 	defer cancel()
 
 	// Use the OpenAPI viewer to get documentation
@@ -477,6 +524,11 @@ func (s *Server) executeHTTPRequest(id interface{}, args map[string]interface{})
 	requestConfig := *s.config
 	requestConfig.Methods = []string{method}
 
+	// Validation against the spec is opt-in, as with --validate on the CLI
+	if validate, ok := args["validate"].(bool); ok && validate {
+		requestConfig.ValidateRequest = true
+	}
+
 	// Start with inherited headers from MCP config
 	requestConfig.Headers = append([]string{}, s.config.MCP.Headers...)
 
@@ -499,9 +551,9 @@ func (s *Server) executeHTTPRequest(id interface{}, args map[string]interface{})
 		}
 	}
 
-	// Handle body
+	// Handle body. DataRaw keeps a body starting with @ from reading local files.
 	if body, ok := args["body"].(string); ok {
-		requestConfig.Data = body
+		requestConfig.DataRaw = body
 	}
 
 	s.logger.Debug().
@@ -509,7 +561,7 @@ func (s *Server) executeHTTPRequest(id interface{}, args map[string]interface{})
 		Str("path", path).
 		Int("headers", len(requestConfig.Headers)).
 		Int("query_params", len(requestConfig.QueryParams)).
-		Bool("has_body", requestConfig.Data != "").
+		Bool("has_body", requestConfig.DataRaw != "").
 		Msg("executing HTTP request via MCP")
 
 	// Create a new HTTP client with the request-specific config
@@ -520,35 +572,41 @@ func (s *Server) executeHTTPRequest(id interface{}, args map[string]interface{})
 		return s.sendError(id, -32603, fmt.Sprintf("Failed to create HTTP executor: %v", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := s.requestContext(30 * time.Second)
 	defer cancel()
 
 	// Execute the request and capture response
 	body, headers, statusCode, err := executor.ExecuteForMCP(ctx, path)
 	if err != nil {
+		if errors.IsType(err, errors.ErrorTypeValidation) {
+			s.logger.Debug().Err(err).Msg("request rejected by validation")
+			return s.sendError(id, -32602, validationMessage(err))
+		}
 		s.logger.Error().Err(err).Msg("HTTP request failed via MCP")
 		return s.sendError(id, -32603, fmt.Sprintf("HTTP request failed: %v", err))
 	}
 
-	// Check for filter parameters
-	regexPattern, hasRegex := args["regex"].(string)
-	jmespathExpr, hasJMESPath := args["jmespath"].(string)
-
-	// Empty strings should not trigger filtering
-	if hasRegex && strings.TrimSpace(regexPattern) == "" {
-		hasRegex = false
+	// Check for filter parameters; empty strings should not trigger filtering
+	regexPattern, _ := args["regex"].(string)
+	var filters []string
+	if strings.TrimSpace(regexPattern) != "" {
+		filters = append(filters, "regex")
 	}
-	if hasJMESPath && strings.TrimSpace(jmespathExpr) == "" {
-		hasJMESPath = false
+	var language, expression string
+	for _, name := range filter.Languages() {
+		if expr, ok := args[name].(string); ok && strings.TrimSpace(expr) != "" {
+			language, expression = name, expr
+			filters = append(filters, name)
+		}
 	}
 
 	// Validate mutual exclusivity
-	if hasRegex && hasJMESPath {
-		return s.sendError(id, -32602, "Cannot use both regex and jmespath filters simultaneously")
+	if len(filters) > 1 {
+		return s.sendError(id, -32602, fmt.Sprintf("Cannot use %s filters simultaneously", strings.Join(filters, " and ")))
 	}
 
 	// Apply regex filter if requested
-	if hasRegex {
+	if len(filters) == 1 && filters[0] == "regex" {
 		contextLines := 5 // default
 		if cl, ok := args["context_lines"].(float64); ok {
 			contextLines = int(cl)
@@ -559,7 +617,7 @@ func (s *Server) executeHTTPRequest(id interface{}, args map[string]interface{})
 			Int("context_lines", contextLines).
 			Msg("applying regex filter")
 
-		filterResult, err := filterRegex(body, regexPattern, contextLines)
+		filterResult, err := filter.Regex(body, regexPattern, contextLines)
 		if err != nil {
 			s.logger.Error().Err(err).Msg("regex filter failed")
 			return s.sendError(id, -32603, fmt.Sprintf("Regex filter failed: %v", err))
@@ -568,16 +626,17 @@ func (s *Server) executeHTTPRequest(id interface{}, args map[string]interface{})
 		return s.sendFilteredResponse(id, filterResult, statusCode, headers, &requestConfig)
 	}
 
-	// Apply jmespath filter if requested
-	if hasJMESPath {
+	// Apply a query language filter if requested
+	if language != "" {
 		s.logger.Debug().
-			Str("expression", jmespathExpr).
-			Msg("applying jmespath filter")
+			Str("language", language).
+			Str("expression", expression).
+			Msg("applying query filter")
 
-		filterResult, err := filterJMESPath(body, jmespathExpr)
+		filterResult, err := filter.Search(language, body, expression)
 		if err != nil {
-			s.logger.Error().Err(err).Msg("jmespath filter failed")
-			return s.sendError(id, -32603, fmt.Sprintf("JMESPath filter failed: %v", err))
+			s.logger.Error().Err(err).Str("language", language).Msg("query filter failed")
+			return s.sendError(id, -32603, fmt.Sprintf("%s filter failed: %v", displayName(language), err))
 		}
 
 		return s.sendFilteredResponse(id, filterResult, statusCode, headers, &requestConfig)
@@ -620,7 +679,7 @@ func (s *Server) executeHTTPRequest(id interface{}, args map[string]interface{})
 }
 
 // sendFilteredResponse sends an MCP response with filtered content and metadata
-func (s *Server) sendFilteredResponse(id interface{}, filterResult *FilterResult, statusCode int, headers map[string][]string, cfg *config.Config) error {
+func (s *Server) sendFilteredResponse(id interface{}, filterResult *filter.Result, statusCode int, headers map[string][]string, cfg *config.Config) error {
 	contentText := filterResult.Content
 
 	// Prepend status/headers if verbose mode is enabled
@@ -671,6 +730,54 @@ func (s *Server) sendResponse(response MCPResponse) error {
 	return nil
 }
 
+// queryParamDescription describes the execute tool parameter for a query
+// language, which cannot be combined with any other filter
+func queryParamDescription(language filter.Language) string {
+	others := []string{"regex"}
+	for _, name := range filter.Languages() {
+		if name != language.Name() {
+			others = append(others, name)
+		}
+	}
+	list := others[len(others)-1]
+	if len(others) > 1 {
+		list = strings.Join(others[:len(others)-1], ", ") + " or " + list
+	}
+	return fmt.Sprintf("%s\n\nCannot be used with %s.", language.Description(), list)
+}
+
+// displayName returns the name of a query language for error messages
+func displayName(name string) string {
+	if language, err := filter.Lookup(name); err == nil {
+		return language.DisplayName()
+	}
+	return name
+}
+
+// validationMessage formats a validation error, listing each violation on its own line
+// so the caller can correct the request
+func validationMessage(err error) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("Invalid request: %v", err))
+
+	context := errors.GetContext(err)
+	if operation, ok := context["operation"]; ok {
+		message.WriteString(fmt.Sprintf("\nOperation: %v", operation))
+	}
+	if violations, ok := context["violations"].([]openapi.Violation); ok {
+		for _, violation := range violations {
+			message.WriteString("\n- " + violation.String())
+		}
+	}
+	for _, key := range []string{"missing", "unknown", "suggestion"} {
+		if value, ok := context[key]; ok {
+			message.WriteString(fmt.Sprintf("\n%s: %v", key, value))
+		}
+	}
+
+	return message.String()
+}
+
 // sendError sends an MCP error response
 func (s *Server) sendError(id interface{}, code int, message string) error {
 	response := MCPResponse{
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/brendan.keane/qurl/internal/config"
+	"github.com/brendan.keane/qurl/internal/filter"
 	"github.com/rs/zerolog"
 )
 
@@ -131,9 +132,9 @@ func TestFilterRegex_TokenEstimation(t *testing.T) {
 	largeBody += "ERROR: This is the one line that matches\n"
 	largeBody += strings.Repeat("This is another line that should not match.\n", 1000)
 
-	result, err := filterRegex(largeBody, "ERROR", 2)
+	result, err := filter.Regex(largeBody, "ERROR", 2)
 	if err != nil {
-		t.Fatalf("filterRegex failed: %v", err)
+		t.Fatalf("filter.Regex failed: %v", err)
 	}
 
 	// Check that the filtered result is much smaller than the source
@@ -188,9 +189,9 @@ func TestFilterJMESPath_TokenEstimation(t *testing.T) {
 	body, _ := json.Marshal(largeJSON)
 
 	// Filter to only get the failed item
-	result, err := filterJMESPath(string(body), "items[?status=='failed']")
+	result, err := filter.JMESPath(string(body), "items[?status=='failed']")
 	if err != nil {
-		t.Fatalf("filterJMESPath failed: %v", err)
+		t.Fatalf("filter.JMESPath failed: %v", err)
 	}
 
 	sourceTokens := result.Meta["tokens"].(map[string]interface{})["source"].(int)
@@ -2,11 +2,19 @@ package mcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/brendan.keane/qurl/internal/errors"
+	"github.com/brendan.keane/qurl/internal/filter"
 	"github.com/brendan.keane/qurl/internal/testutil"
+	"github.com/brendan.keane/qurl/pkg/openapi"
 	"github.com/rs/zerolog"
 )
 
@@ -59,6 +67,21 @@ func TestNewServer_InvalidConfig(t *testing.T) {
 	}
 }
 
+func TestNewServer_InvalidServerVariable(t *testing.T) {
+	cfg := testutil.NewConfigBuilder().WithMCP().
+		WithOpenAPIURL("https://api.example.com/openapi.json").
+		Build()
+	cfg.ServerVars = []string{"region"}
+
+	server, err := NewServer(zerolog.Nop(), cfg)
+	if !errors.IsType(err, errors.ErrorTypeValidation) {
+		t.Fatalf("expected a validation error for a malformed --server-var, got %v", err)
+	}
+	if server != nil {
+		t.Error("NewServer should return nil server on error")
+	}
+}
+
 func TestMCPRequestSerialization(t *testing.T) {
 	req := MCPRequest{
 		JSONRPC: "2.0",
@@ -314,4 +337,150 @@ func BenchmarkMCPResponseSerialization(b *testing.B) {
 			b.Fatal(err)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestValidationMessage(t *testing.T) {
+	err := errors.New(errors.ErrorTypeValidation, "request does not match the OpenAPI operation").
+		WithContext("operation", "POST /pet").
+		WithContext("violations", []openapi.Violation{
+			{Pointer: "/body/name", Message: "required property is missing"},
+			{Pointer: "/query/limit", Message: `expected integer, got "ten"`},
+		})
+
+	message := validationMessage(err)
+	for _, expected := range []string{
+		"Invalid request: request does not match the OpenAPI operation",
+		"Operation: POST /pet",
+		"- /body/name: required property is missing",
+		`- /query/limit: expected integer, got "ten"`,
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("Expected message to contain %q, got:\n%s", expected, message)
+		}
+	}
+}
+
+func TestServer_RequestContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxTime time.Duration
+		want    time.Duration
+	}{
+		{"default timeout", 0, 30 * time.Second},
+		{"max time", 5 * time.Minute, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.NewConfigBuilder().WithMCP().Build()
+			cfg.Timeouts.MaxTime = tt.maxTime
+
+			server, err := NewServer(zerolog.Nop(), cfg)
+			testutil.AssertNoError(t, err, "NewServer")
+
+			parent, interrupt := context.WithCancel(context.Background())
+			server.ctx = parent
+
+			ctx, cancel := server.requestContext(30 * time.Second)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatal("request context should have a deadline")
+			}
+			if remaining := time.Until(deadline); remaining > tt.want || remaining < tt.want-time.Second {
+				t.Errorf("deadline in %s, expected %s", remaining, tt.want)
+			}
+
+			interrupt()
+			if ctx.Err() != context.Canceled {
+				t.Errorf("interrupting the server should cancel the request, got %v", ctx.Err())
+			}
+		})
+	}
+}
+
+func TestServer_ToolsListFilterParameters(t *testing.T) {
+	cfg := testutil.NewConfigBuilder().WithMCP().Build()
+	server, err := NewServer(zerolog.Nop(), cfg)
+	testutil.AssertNoError(t, err, "NewServer")
+
+	output, err := captureServerOutput(t, server, `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+	testutil.AssertNoError(t, err, "tools/list")
+
+	var response struct {
+		Result struct {
+			Tools []struct {
+				Name        string `json:"name"`
+				InputSchema struct {
+					Properties map[string]interface{} `json:"properties"`
+				} `json:"inputSchema"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal([]byte(output), &response), "decode tools/list response")
+
+	for _, tool := range response.Result.Tools {
+		if tool.Name != "execute" {
+			continue
+		}
+		// Every registered filter language is selectable, alongside regex
+		for _, name := range append([]string{"regex"}, filter.Languages()...) {
+			if _, ok := tool.InputSchema.Properties[name]; !ok {
+				t.Errorf("execute tool schema is missing the %s parameter", name)
+			}
+		}
+		return
+	}
+	t.Fatal("execute tool not listed")
+}
+
+func TestServer_ExecuteValidationIsOptIn(t *testing.T) {
+	spec := `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "1.0"}, "paths": {"/pets": {"get": {"responses": {"200": {"description": "OK"}}}}}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/openapi.json" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(spec))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		arguments string
+		wantError bool
+	}{
+		{"unmatched path executes", `{"path": "/health"}`, false},
+		{"unmatched path rejected when validating", `{"path": "/health", "validate": true}`, true},
+		{"matched path validates", `{"path": "/pets", "validate": true}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.NewConfigBuilder().WithMCP().
+				WithOpenAPIURL(srv.URL + "/openapi.json").
+				WithServer(srv.URL).
+				Build()
+			server, err := NewServer(zerolog.Nop(), cfg)
+			testutil.AssertNoError(t, err, "NewServer")
+
+			input := `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "execute", "arguments": ` + tt.arguments + `}}`
+			output, err := captureServerOutput(t, server, input)
+			testutil.AssertNoError(t, err, "tools/call")
+
+			var response MCPResponse
+			testutil.AssertNoError(t, json.Unmarshal([]byte(output), &response), "decode tools/call response")
+			if tt.wantError {
+				if response.Error == nil || response.Error.Code != -32602 {
+					t.Errorf("expected a validation error, got %s", output)
+				}
+				return
+			}
+			if response.Error != nil {
+				t.Fatalf("unexpected error: %s", response.Error.Message)
+			}
+			testutil.AssertStringContains(t, output, `"text":"ok"`, "response text")
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/brendan.keane/qurl/internal/config"
 	httpinternal "github.com/brendan.keane/qurl/internal/http"
+	"github.com/brendan.keane/qurl/pkg/openapi"
 	"github.com/rs/zerolog"
 )
 
@@ -57,8 +58,10 @@ type MockOpenAPIProvider struct {
 	ViewError       error
 	BaseURLResult   string
 	BaseURLError    error
-	Servers         []string
+	Servers         []openapi.ServerInfo
 	ServersError    error
+	Match           *openapi.PathMatch
+	MatchError      error
 	SetHeadersCalls []SetHeadersCall // Track calls for assertions
 	ViewCalls       []ViewCall
 }
@@ -91,17 +94,21 @@ func (m *MockOpenAPIProvider) BaseURL(ctx context.Context) (string, error) {
 	return m.BaseURLResult, m.BaseURLError
 }
 
-func (m *MockOpenAPIProvider) GetServers() ([]string, error) {
+func (m *MockOpenAPIProvider) GetServers(ctx context.Context) ([]openapi.ServerInfo, error) {
 	return m.Servers, m.ServersError
 }
 
+func (m *MockOpenAPIProvider) MatchPath(ctx context.Context, path, method string) (*openapi.PathMatch, error) {
+	return m.Match, m.MatchError
+}
+
 // NewMockOpenAPIProvider creates a mock OpenAPI provider with common defaults
 func NewMockOpenAPIProvider() *MockOpenAPIProvider {
 	return &MockOpenAPIProvider{
 		Headers:         make(map[string]string),
 		ViewResult:      "# API Documentation",
 		BaseURLResult:   "https://api.example.com",
-		Servers:         []string{"https://api.example.com"},
+		Servers:         []openapi.ServerInfo{{URL: "https://api.example.com"}},
 		SetHeadersCalls: make([]SetHeadersCall, 0),
 		ViewCalls:       make([]ViewCall, 0),
 	}
@@ -149,6 +156,11 @@ func (m *MockResponseHandler) HandleResponse(resp *http.Response, method, target
 	return m.Error
 }
 
+func (m *MockResponseHandler) HandleEventStream(resp *http.Response, method, targetURL string, state *httpinternal.EventStreamState) error {
+	m.HandleCalls = append(m.HandleCalls, HandleCall{Method: method, TargetURL: targetURL})
+	return m.Error
+}
+
 func (m *MockResponseHandler) HandleResponseForMCP(resp *http.Response, method, targetURL string) (string, map[string][]string, int, error) {
 	m.HandleMCPCalls = append(m.HandleMCPCalls, HandleCall{Method: method, TargetURL: targetURL})
 	if m.MCPError != nil {
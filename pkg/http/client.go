@@ -18,13 +18,19 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
 )
 
-// LambdaConfig holds default configuration for Lambda event conversion
+// LambdaConfig holds the request context used for Lambda event conversion.
+// Empty fields take their values from DefaultLambdaConfig.
 type LambdaConfig struct {
-	AccountID    string
-	APIID        string
-	DomainName   string
-	DomainPrefix string
-	UserAgent    string
+	AccountID      string
+	APIID          string
+	DomainName     string
+	DomainPrefix   string
+	UserAgent      string
+	Stage          string
+	SourceIP       string
+	StageVariables map[string]string
+	PathParameters map[string]string
+	Authorizer     LambdaAuthorizer
 }
 
 // DefaultLambdaConfig returns the default Lambda configuration
@@ -35,6 +41,8 @@ func DefaultLambdaConfig() LambdaConfig {
 		DomainName:   "lambda.local",   // Dummy domain
 		DomainPrefix: "lambda",         // Dummy prefix
 		UserAgent:    "qurl",
+		Stage:        "$default",
+		SourceIP:     "127.0.0.1",
 	}
 }
 
@@ -42,6 +50,7 @@ func DefaultLambdaConfig() LambdaConfig {
 // Lambda client is initialized lazily on first lambda:// request
 type Client struct {
 	*http.Client
+	Lambda       LambdaOptions
 	lambdaClient *lambda.Client
 	awsConfig    *aws.Config
 	initOnce     sync.Once
@@ -73,23 +82,49 @@ func (c *Client) initLambdaClient(ctx context.Context) error {
 			c.initErr = fmt.Errorf("loading AWS config: %w", err)
 			return
 		}
+		if c.Lambda.Endpoint != "" {
+			cfg = localLambdaConfig(ctx, cfg)
+		}
 		c.awsConfig = &cfg
-		c.lambdaClient = lambda.NewFromConfig(cfg)
+		c.lambdaClient = lambda.NewFromConfig(cfg, func(o *lambda.Options) {
+			if c.Lambda.Endpoint != "" {
+				o.BaseEndpoint = aws.String(c.Lambda.Endpoint)
+			}
+		})
 	})
 	return c.initErr
 }
 
+// localLambdaConfig adapts the AWS config for a local Lambda endpoint such as
+// the Runtime Interface Emulator or sam local start-lambda. Neither needs a
+// real region or credentials, so a default region is used when none is set and
+// requests are sent unsigned when no credentials can be found.
+func localLambdaConfig(ctx context.Context, cfg aws.Config) aws.Config {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Credentials == nil {
+		cfg.Credentials = aws.AnonymousCredentials{}
+	} else if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		cfg.Credentials = aws.AnonymousCredentials{}
+	}
+	return cfg
+}
+
 // Do performs the request, routing to Lambda or HTTP based on the URL scheme
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
-	if req.URL.Scheme == "lambda" {
-		return c.doLambda(req)
+	if format, ok := lambdaSchemeFormat(req.URL.Scheme); ok {
+		if format == "" {
+			format = c.Lambda.Format
+		}
+		return c.doLambda(req, format)
 	}
 	return c.Client.Do(req)
 }
 
 // Get performs a GET request
 func (c *Client) Get(url string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", NormalizeLambdaURL(url), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +133,7 @@ func (c *Client) Get(url string) (*http.Response, error) {
 
 // Post performs a POST request
 func (c *Client) Post(url, contentType string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest("POST", url, body)
+	req, err := http.NewRequest("POST", NormalizeLambdaURL(url), body)
 	if err != nil {
 		return nil, err
 	}
@@ -106,21 +141,25 @@ func (c *Client) Post(url, contentType string, body io.Reader) (*http.Response,
 	return c.Do(req)
 }
 
-// doLambda handles Lambda invocations
-func (c *Client) doLambda(req *http.Request) (*http.Response, error) {
+// doLambda handles Lambda invocations, using the given proxy event format
+func (c *Client) doLambda(req *http.Request, format LambdaFormat) (*http.Response, error) {
+	// Extract the function, qualifier and region from the URL
+	target, err := ParseLambdaTarget(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize Lambda client on first use
 	if err := c.initLambdaClient(req.Context()); err != nil {
 		return nil, err
 	}
 
-	// Extract Lambda function name from hostname
-	functionName := req.URL.Host
-	if functionName == "" {
-		return nil, fmt.Errorf("lambda URL missing function name")
-	}
+	// The event's Host header is the function name, not the qualifier or ARN
+	req = req.Clone(req.Context())
+	req.Host = target.Name()
 
 	// Convert HTTP request to Lambda proxy event
-	event, err := httpRequestToLambdaEvent(req)
+	event, err := lambdaEvent(req, format, c.Lambda.Event)
 	if err != nil {
 		return nil, fmt.Errorf("converting request to Lambda event: %w", err)
 	}
@@ -137,53 +176,76 @@ func (c *Client) doLambda(req *http.Request) (*http.Response, error) {
 		ctx = context.Background()
 	}
 
-	output, err := c.lambdaClient.Invoke(ctx, &lambda.InvokeInput{
-		FunctionName:   aws.String(functionName),
-		InvocationType: types.InvocationTypeRequestResponse,
+	invocationType := c.Lambda.InvocationType
+	if invocationType == "" {
+		invocationType = types.InvocationTypeRequestResponse
+	}
+
+	input := &lambda.InvokeInput{
+		FunctionName:   aws.String(target.FunctionName),
+		InvocationType: invocationType,
 		Payload:        payload,
-	})
+	}
+	if target.Qualifier != "" {
+		input.Qualifier = aws.String(target.Qualifier)
+	}
+	// Log tails are only returned for synchronous invocations
+	if c.Lambda.LogWriter != nil && invocationType == types.InvocationTypeRequestResponse {
+		input.LogType = types.LogTypeTail
+	}
+
+	// Cross-region ARNs must be invoked in the function's region
+	var optFns []func(*lambda.Options)
+	if target.Region != "" {
+		optFns = append(optFns, func(o *lambda.Options) {
+			o.Region = target.Region
+		})
+	}
+
+	if c.Lambda.Stream && invocationType == types.InvocationTypeRequestResponse {
+		return c.invokeStream(ctx, req, target, input, format, optFns...)
+	}
+
+	output, err := c.lambdaClient.Invoke(ctx, input, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("invoking Lambda function: %w", err)
 	}
 
+	// Event and DryRun invocations return no function response
+	if invocationType != types.InvocationTypeRequestResponse {
+		resp := lambdaInvocationResponse(invocationType, output)
+		resp.Request = req
+		return resp, nil
+	}
+
+	// Logs are written before errors are checked, since they explain most failures
+	if err := writeLambdaLogs(c.Lambda.LogWriter, output.LogResult); err != nil {
+		return nil, err
+	}
+
 	// Check for Lambda errors
 	if output.FunctionError != nil {
-		return nil, fmt.Errorf("Lambda function error: %s", *output.FunctionError)
+		return nil, parseLambdaFunctionError(target, *output.FunctionError, output.Payload)
 	}
 
 	// Convert Lambda response to HTTP response
-	return lambdaResponseToHTTP(output.Payload)
+	resp, err := lambdaResponseToHTTPFormat(output.Payload, format)
+	if err != nil {
+		return nil, err
+	}
+	resp.Request = req
+	return resp, nil
 }
 
 // httpRequestToLambdaEvent converts an http.Request to an API Gateway v2 HTTP proxy event
-func httpRequestToLambdaEvent(req *http.Request) (*events.APIGatewayV2HTTPRequest, error) {
-	// Read body if present
-	var bodyString string
-	var isBase64Encoded bool
-
-	if req.Body != nil {
-		bodyBytes, err := io.ReadAll(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("reading request body: %w", err)
-		}
-		// Restore body for potential retries
-		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-
-		// For now, we'll send everything as plain text
-		bodyString = string(bodyBytes)
-		isBase64Encoded = false
+func httpRequestToLambdaEvent(req *http.Request, config LambdaConfig) (*events.APIGatewayV2HTTPRequest, error) {
+	bodyString, isBase64Encoded, err := lambdaRequestBody(req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build headers map
-	headers := make(map[string]string)
-	for key, values := range req.Header {
-		headers[key] = strings.Join(values, ",")
-	}
-
-	// Add Host header from req.Host (it's not in req.Header in Go's http package)
-	if req.Host != "" {
-		headers["Host"] = req.Host
-	}
+	headers, _ := lambdaHeaders(req)
 
 	// Build query string parameters
 	queryParams := make(map[string]string)
@@ -191,8 +253,8 @@ func httpRequestToLambdaEvent(req *http.Request) (*events.APIGatewayV2HTTPReques
 		queryParams[key] = strings.Join(values, ",")
 	}
 
-	// Get default Lambda configuration
-	config := DefaultLambdaConfig()
+	// Fill in the request context fields that were not configured
+	config = config.withDefaults()
 
 	// Construct API Gateway v2 event structure using the official type
 	event := &events.APIGatewayV2HTTPRequest{
@@ -202,6 +264,8 @@ func httpRequestToLambdaEvent(req *http.Request) (*events.APIGatewayV2HTTPReques
 		RawQueryString:        req.URL.RawQuery,
 		Headers:               headers,
 		QueryStringParameters: queryParams,
+		PathParameters:        config.PathParameters,
+		StageVariables:        config.StageVariables,
 		RequestContext: events.APIGatewayV2HTTPRequestContext{
 			AccountID:    config.AccountID,
 			APIID:        config.APIID,
@@ -211,14 +275,15 @@ func httpRequestToLambdaEvent(req *http.Request) (*events.APIGatewayV2HTTPReques
 				Method:    req.Method,
 				Path:      req.URL.Path,
 				Protocol:  "HTTP/1.1",
-				SourceIP:  "127.0.0.1",
+				SourceIP:  config.SourceIP,
 				UserAgent: config.UserAgent,
 			},
-			RequestID: fmt.Sprintf("qurl-%d", time.Now().UnixNano()),
-			RouteKey:  fmt.Sprintf("%s %s", req.Method, req.URL.Path),
-			Stage:     "$default",
-			Time:      time.Now().Format("02/Jan/2006:15:04:05 -0700"),
-			TimeEpoch: time.Now().UnixMilli(),
+			Authorizer: config.Authorizer.v2Authorizer(),
+			RequestID:  fmt.Sprintf("qurl-%d", time.Now().UnixNano()),
+			RouteKey:   fmt.Sprintf("%s %s", req.Method, req.URL.Path),
+			Stage:      config.Stage,
+			Time:       time.Now().Format("02/Jan/2006:15:04:05 -0700"),
+			TimeEpoch:  time.Now().UnixMilli(),
 		},
 		Body:            bodyString,
 		IsBase64Encoded: isBase64Encoded,
@@ -227,7 +292,36 @@ func httpRequestToLambdaEvent(req *http.Request) (*events.APIGatewayV2HTTPReques
 	return event, nil
 }
 
-// lambdaResponseToHTTP converts a Lambda response to an http.Response
+// lambdaRequestBody reads the request body for a Lambda event, base64-encoding
+// it when it is not text. The request body is left unread for retries.
+func lambdaRequestBody(req *http.Request) (string, bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", false, nil
+	}
+
+	var bodyBytes []byte
+	var err error
+	if req.GetBody != nil {
+		// Read a fresh copy so the request body stays unread for retries
+		var body io.ReadCloser
+		if body, err = req.GetBody(); err == nil {
+			bodyBytes, err = io.ReadAll(body)
+			body.Close()
+		}
+	} else {
+		bodyBytes, err = io.ReadAll(req.Body)
+		// Restore body for potential retries
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("reading request body: %w", err)
+	}
+
+	body, isBase64Encoded := encodeLambdaBody(req.Header, bodyBytes)
+	return body, isBase64Encoded, nil
+}
+
+// lambdaResponseToHTTP converts an API Gateway v2 Lambda response to an http.Response
 func lambdaResponseToHTTP(payload []byte) (*http.Response, error) {
 	// Parse the Lambda response using the official type
 	var lambdaResp events.APIGatewayV2HTTPResponse
@@ -236,10 +330,31 @@ func lambdaResponseToHTTP(payload []byte) (*http.Response, error) {
 		return nil, fmt.Errorf("parsing Lambda response: %w", err)
 	}
 
-	// Create HTTP response
+	resp, err := newLambdaHTTPResponse(lambdaResp.StatusCode, "", lambdaResp.Headers, lambdaResp.MultiValueHeaders, lambdaResp.Body, lambdaResp.IsBase64Encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	// Payload 2.0 returns cookies separately from headers
+	for _, cookie := range lambdaResp.Cookies {
+		resp.Header.Add("Set-Cookie", cookie)
+	}
+
+	return resp, nil
+}
+
+// newLambdaHTTPResponse builds an http.Response from the fields shared by all
+// Lambda proxy response formats. Multi-value headers take precedence over
+// single-value ones with the same name.
+func newLambdaHTTPResponse(statusCode int, statusDescription string, headers map[string]string, multiValueHeaders map[string][]string, body string, isBase64Encoded bool) (*http.Response, error) {
+	status := fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
+	if statusDescription != "" {
+		status = statusDescription
+	}
+
 	resp := &http.Response{
-		StatusCode: lambdaResp.StatusCode,
-		Status:     fmt.Sprintf("%d %s", lambdaResp.StatusCode, http.StatusText(lambdaResp.StatusCode)),
+		StatusCode: statusCode,
+		Status:     status,
 		Header:     make(http.Header),
 		Proto:      "HTTP/1.1",
 		ProtoMajor: 1,
@@ -247,26 +362,23 @@ func lambdaResponseToHTTP(payload []byte) (*http.Response, error) {
 	}
 
 	// Set headers
-	for key, value := range lambdaResp.Headers {
+	for key, value := range headers {
 		resp.Header.Set(key, value)
 	}
+	for key, values := range multiValueHeaders {
+		resp.Header.Del(key)
+		for _, value := range values {
+			resp.Header.Add(key, value)
+		}
+	}
 
 	// Set body
-	if lambdaResp.Body != "" {
-		var bodyBytes []byte
-		if lambdaResp.IsBase64Encoded {
-			// Decode base64 if needed
-			// For now, treating as plain text
-			bodyBytes = []byte(lambdaResp.Body)
-		} else {
-			bodyBytes = []byte(lambdaResp.Body)
-		}
-		resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-		resp.ContentLength = int64(len(bodyBytes))
-	} else {
-		resp.Body = io.NopCloser(bytes.NewReader([]byte{}))
-		resp.ContentLength = 0
+	bodyBytes, err := decodeLambdaBody(body, isBase64Encoded)
+	if err != nil {
+		return nil, err
 	}
+	resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	resp.ContentLength = int64(len(bodyBytes))
 
 	return resp, nil
 }
@@ -3,6 +3,7 @@ package http
 import (
 	"bytes"
 	"encoding/base64"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -104,7 +105,7 @@ func TestHTTPRequestToLambdaEvent_EdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			event, err := httpRequestToLambdaEvent(tt.req)
+			event, err := httpRequestToLambdaEvent(tt.req, DefaultLambdaConfig())
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("httpRequestToLambdaEvent() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -153,13 +154,21 @@ func TestLambdaResponseToHTTP_EdgeCases(t *testing.T) {
 				"isBase64Encoded": true
 			}`),
 			check: func(t *testing.T, resp *http.Response) {
-				// Note: Current implementation doesn't decode base64
-				// This is a test to document current behavior
-				if resp.Body == nil {
-					t.Error("Expected non-nil body")
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != "Hello World" {
+					t.Errorf("Expected decoded body %q, got %q", "Hello World", body)
 				}
 			},
 		},
+		{
+			name: "invalid base64 body",
+			payload: []byte(`{
+				"statusCode": 200,
+				"body": "not base64!",
+				"isBase64Encoded": true
+			}`),
+			wantErr: true,
+		},
 		{
 			name: "no body field",
 			payload: []byte(`{
@@ -231,9 +240,9 @@ func TestClientValidation(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "Lambda URL with port (invalid)",
+			name:    "Lambda URL with numeric version",
 			url:     "lambda://my-function:8080/path",
-			wantErr: false, // Port becomes part of function name - document this behavior
+			wantErr: false, // Parsed as version 8080 of my-function by ParseLambdaTarget
 		},
 	}
 
@@ -35,7 +35,7 @@ func TestHTTPRequestToLambdaEvent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			event, err := httpRequestToLambdaEvent(tt.req)
+			event, err := httpRequestToLambdaEvent(tt.req, DefaultLambdaConfig())
 			if err != nil {
 				t.Fatalf("httpRequestToLambdaEvent() error = %v", err)
 			}
@@ -280,7 +280,7 @@ func TestHTTPRequestToLambdaEvent_HostHeader(t *testing.T) {
 				t.Fatalf("Failed to create request: %v", err)
 			}
 
-			event, err := httpRequestToLambdaEvent(req)
+			event, err := httpRequestToLambdaEvent(req, DefaultLambdaConfig())
 			if err != nil {
 				t.Fatalf("httpRequestToLambdaEvent() error = %v", err)
 			}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// encodeLambdaBody prepares a request body for a Lambda proxy event. Text bodies
+// are sent as-is; anything else is base64-encoded, as API Gateway does for binary
+// media types, so bytes survive the JSON payload unchanged.
+func encodeLambdaBody(header http.Header, body []byte) (string, bool) {
+	if len(body) == 0 {
+		return "", false
+	}
+
+	if isTextBody(header, body) {
+		return string(body), false
+	}
+
+	return base64.StdEncoding.EncodeToString(body), true
+}
+
+// decodeLambdaBody returns the raw bytes of a Lambda proxy response body
+func decodeLambdaBody(body string, isBase64Encoded bool) ([]byte, error) {
+	if !isBase64Encoded {
+		return []byte(body), nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64 response body: %w", err)
+	}
+	return decoded, nil
+}
+
+// isTextBody reports whether a body can travel as a JSON string without loss.
+// Compressed bodies are always binary. Without a Content-Type the body is
+// treated as text when it is valid UTF-8.
+func isTextBody(header http.Header, body []byte) bool {
+	if encoding := header.Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		return false
+	}
+
+	if !utf8.Valid(body) {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+
+	return isTextContentType(contentType)
+}
+
+// isTextContentType reports whether a media type carries text
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+
+	switch mediaType {
+	case "application/json",
+		"application/xml",
+		"application/x-www-form-urlencoded",
+		"application/javascript",
+		"application/graphql",
+		"application/x-yaml",
+		"application/yaml",
+		"application/x-ndjson":
+		return true
+	}
+
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") || strings.HasSuffix(mediaType, "+yaml")
+}
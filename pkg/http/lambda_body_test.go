@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestEncodeLambdaBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		body       []byte
+		wantBase64 bool
+	}{
+		{name: "empty body", body: nil},
+		{name: "JSON", headers: map[string]string{"Content-Type": "application/json"}, body: []byte(`{"a":1}`)},
+		{name: "vendor JSON", headers: map[string]string{"Content-Type": "application/vnd.api+json; charset=utf-8"}, body: []byte(`{}`)},
+		{name: "plain text", headers: map[string]string{"Content-Type": "text/plain"}, body: []byte("héllo")},
+		{name: "no content type, UTF-8", body: []byte("name=doggie")},
+		{name: "no content type, binary", body: []byte{0xff, 0x00}, wantBase64: true},
+		{name: "image", headers: map[string]string{"Content-Type": "image/png"}, body: []byte("\x89PNG"), wantBase64: true},
+		{name: "protobuf", headers: map[string]string{"Content-Type": "application/x-protobuf"}, body: []byte("\x08\x96\x01"), wantBase64: true},
+		{name: "gzip JSON", headers: map[string]string{"Content-Type": "application/json", "Content-Encoding": "gzip"}, body: []byte("\x1f\x8b"), wantBase64: true},
+		{name: "invalid UTF-8 text", headers: map[string]string{"Content-Type": "text/plain"}, body: []byte{'a', 0xff}, wantBase64: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			for key, value := range tt.headers {
+				header.Set(key, value)
+			}
+
+			encoded, isBase64 := encodeLambdaBody(header, tt.body)
+			if isBase64 != tt.wantBase64 {
+				t.Fatalf("Expected isBase64Encoded=%v, got %v", tt.wantBase64, isBase64)
+			}
+
+			decoded, err := decodeLambdaBody(encoded, isBase64)
+			if err != nil {
+				t.Fatalf("Unexpected decode error: %v", err)
+			}
+			if !bytes.Equal(decoded, tt.body) && !(len(decoded) == 0 && len(tt.body) == 0) {
+				t.Errorf("Round trip changed body: got %q, want %q", decoded, tt.body)
+			}
+		})
+	}
+}
+
+// echoLambda is a fake Lambda handler that returns the request body with its
+// Content-Type, base64-encoding non-text bodies like a real proxy integration
+func echoLambda(t *testing.T, payload []byte) []byte {
+	t.Helper()
+
+	var event events.APIGatewayV2HTTPRequest
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("Fake Lambda received invalid event: %v", err)
+	}
+
+	body := []byte(event.Body)
+	if event.IsBase64Encoded {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(event.Body); err != nil {
+			t.Fatalf("Fake Lambda received invalid base64 body: %v", err)
+		}
+	}
+
+	contentType := event.Headers["Content-Type"]
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode:      200,
+		Headers:         map[string]string{"Content-Type": contentType},
+		Body:            string(body),
+		IsBase64Encoded: !isTextContentType(contentType),
+	}
+	if response.IsBase64Encoded {
+		response.Body = base64.StdEncoding.EncodeToString(body)
+	}
+
+	out, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Fake Lambda failed to marshal response: %v", err)
+	}
+	return out
+}
+
+func TestLambdaBinaryRoundTrip(t *testing.T) {
+	gzipped := []byte{0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x80, 0x7f}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+	}{
+		{name: "binary", contentType: "application/octet-stream", body: gzipped},
+		{name: "image", contentType: "image/png", body: []byte("\x89PNG\r\n\x1a\n\x00\x00")},
+		{name: "JSON", contentType: "application/json", body: []byte(`{"name":"doggie"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "lambda://my-func/upload", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			event, err := httpRequestToLambdaEvent(req, DefaultLambdaConfig())
+			if err != nil {
+				t.Fatalf("httpRequestToLambdaEvent() error = %v", err)
+			}
+			payload, err := json.Marshal(event)
+			if err != nil {
+				t.Fatalf("Failed to marshal event: %v", err)
+			}
+
+			resp, err := lambdaResponseToHTTP(echoLambda(t, payload))
+			if err != nil {
+				t.Fatalf("lambdaResponseToHTTP() error = %v", err)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if !bytes.Equal(body, tt.body) {
+				t.Errorf("Body corrupted in round trip: got %x, want %x", body, tt.body)
+			}
+			if resp.ContentLength != int64(len(tt.body)) {
+				t.Errorf("Expected ContentLength %d, got %d", len(tt.body), resp.ContentLength)
+			}
+		})
+	}
+}
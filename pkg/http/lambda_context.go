@@ -0,0 +1,110 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// LambdaAuthorizer is the authorizer output placed in synthetic events, as if
+// the request had passed an API Gateway authorizer
+type LambdaAuthorizer struct {
+	JWTClaims  map[string]string      // Claims from a JWT (or Cognito user pool) authorizer
+	JWTScopes  []string               // Scopes from a JWT authorizer (v2 only)
+	Lambda     map[string]interface{} // Context returned by a Lambda authorizer
+	IAMUserARN string                 // Caller ARN for IAM authorization
+}
+
+// IsZero reports whether no authorizer output is configured
+func (a LambdaAuthorizer) IsZero() bool {
+	return len(a.JWTClaims) == 0 && len(a.JWTScopes) == 0 && len(a.Lambda) == 0 && a.IAMUserARN == ""
+}
+
+// withDefaults fills empty fields from DefaultLambdaConfig, so a partially
+// configured request context still produces a complete event
+func (c LambdaConfig) withDefaults() LambdaConfig {
+	defaults := DefaultLambdaConfig()
+	if c.AccountID == "" {
+		c.AccountID = defaults.AccountID
+	}
+	if c.APIID == "" {
+		c.APIID = defaults.APIID
+	}
+	if c.DomainName == "" {
+		c.DomainName = defaults.DomainName
+	}
+	if c.DomainPrefix == "" {
+		c.DomainPrefix = defaults.DomainPrefix
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = defaults.UserAgent
+	}
+	if c.Stage == "" {
+		c.Stage = defaults.Stage
+	}
+	if c.SourceIP == "" {
+		c.SourceIP = defaults.SourceIP
+	}
+	return c
+}
+
+// v2Authorizer returns the payload 2.0 authorizer description, or nil when
+// no authorizer is configured
+func (a LambdaAuthorizer) v2Authorizer() *events.APIGatewayV2HTTPRequestContextAuthorizerDescription {
+	if a.IsZero() {
+		return nil
+	}
+
+	authorizer := &events.APIGatewayV2HTTPRequestContextAuthorizerDescription{}
+	if len(a.JWTClaims) > 0 || len(a.JWTScopes) > 0 {
+		authorizer.JWT = &events.APIGatewayV2HTTPRequestContextAuthorizerJWTDescription{
+			Claims: a.JWTClaims,
+			Scopes: a.JWTScopes,
+		}
+	}
+	if len(a.Lambda) > 0 {
+		authorizer.Lambda = a.Lambda
+	}
+	if a.IAMUserARN != "" {
+		authorizer.IAM = &events.APIGatewayV2HTTPRequestContextAuthorizerIAMDescription{
+			AccountID: arnAccountID(a.IAMUserARN),
+			UserARN:   a.IAMUserARN,
+		}
+	}
+	return authorizer
+}
+
+// v1Authorizer returns the REST API authorizer map: Lambda authorizer context
+// at the top level and JWT claims under "claims", as for Cognito user pools
+func (a LambdaAuthorizer) v1Authorizer() map[string]interface{} {
+	if len(a.JWTClaims) == 0 && len(a.Lambda) == 0 {
+		return nil
+	}
+
+	authorizer := make(map[string]interface{}, len(a.Lambda)+1)
+	for key, value := range a.Lambda {
+		authorizer[key] = value
+	}
+	if len(a.JWTClaims) > 0 {
+		authorizer["claims"] = a.JWTClaims
+	}
+	return authorizer
+}
+
+// v1Identity adds the IAM caller to a REST API request identity
+func (a LambdaAuthorizer) v1Identity(identity events.APIGatewayRequestIdentity) events.APIGatewayRequestIdentity {
+	if a.IAMUserARN != "" {
+		identity.AccountID = arnAccountID(a.IAMUserARN)
+		identity.UserArn = a.IAMUserARN
+	}
+	return identity
+}
+
+// arnAccountID returns the account ID field of an ARN, or "" if it has none
+func arnAccountID(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return ""
+	}
+	return parts[4]
+}
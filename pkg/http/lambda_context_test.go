@@ -0,0 +1,109 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestLambdaEventRequestContext(t *testing.T) {
+	config := LambdaConfig{
+		Stage:          "prod",
+		SourceIP:       "203.0.113.7",
+		StageVariables: map[string]string{"table": "users-prod"},
+		PathParameters: map[string]string{"id": "42"},
+		Authorizer: LambdaAuthorizer{
+			JWTClaims:  map[string]string{"sub": "user-1", "cognito:groups": "admin"},
+			JWTScopes:  []string{"users/read"},
+			Lambda:     map[string]interface{}{"tenant": "acme"},
+			IAMUserARN: "arn:aws:iam::210987654321:user/alice",
+		},
+	}
+
+	t.Run("v2", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "lambda://my-function/users/42", nil)
+		event, err := httpRequestToLambdaEvent(req, config)
+		if err != nil {
+			t.Fatalf("httpRequestToLambdaEvent() error = %v", err)
+		}
+
+		ctx := event.RequestContext
+		if ctx.Stage != "prod" || ctx.HTTP.SourceIP != "203.0.113.7" {
+			t.Errorf("stage = %q, source IP = %q", ctx.Stage, ctx.HTTP.SourceIP)
+		}
+		if ctx.AccountID != "123456789012" || ctx.APIID != "lambda-adapter" {
+			t.Errorf("unset fields should use defaults, got account %q, API %q", ctx.AccountID, ctx.APIID)
+		}
+		if !reflect.DeepEqual(event.StageVariables, config.StageVariables) {
+			t.Errorf("StageVariables = %v", event.StageVariables)
+		}
+		if !reflect.DeepEqual(event.PathParameters, config.PathParameters) {
+			t.Errorf("PathParameters = %v", event.PathParameters)
+		}
+
+		if ctx.Authorizer == nil || ctx.Authorizer.JWT == nil || ctx.Authorizer.IAM == nil {
+			t.Fatalf("Authorizer = %+v, want JWT and IAM", ctx.Authorizer)
+		}
+		if ctx.Authorizer.JWT.Claims["sub"] != "user-1" || !reflect.DeepEqual(ctx.Authorizer.JWT.Scopes, []string{"users/read"}) {
+			t.Errorf("JWT = %+v", ctx.Authorizer.JWT)
+		}
+		if ctx.Authorizer.Lambda["tenant"] != "acme" {
+			t.Errorf("Lambda = %v", ctx.Authorizer.Lambda)
+		}
+		if ctx.Authorizer.IAM.AccountID != "210987654321" || ctx.Authorizer.IAM.UserARN != config.Authorizer.IAMUserARN {
+			t.Errorf("IAM = %+v", ctx.Authorizer.IAM)
+		}
+	})
+
+	t.Run("v1", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "lambda+v1://my-function/users/42", nil)
+		event, err := httpRequestToV1Event(req, config)
+		if err != nil {
+			t.Fatalf("httpRequestToV1Event() error = %v", err)
+		}
+
+		ctx := event.RequestContext
+		if ctx.Stage != "prod" || ctx.Identity.SourceIP != "203.0.113.7" {
+			t.Errorf("stage = %q, source IP = %q", ctx.Stage, ctx.Identity.SourceIP)
+		}
+		if ctx.Identity.UserArn != config.Authorizer.IAMUserARN || ctx.Identity.AccountID != "210987654321" {
+			t.Errorf("Identity = %+v", ctx.Identity)
+		}
+		if ctx.Authorizer["tenant"] != "acme" {
+			t.Errorf("Authorizer[tenant] = %v", ctx.Authorizer["tenant"])
+		}
+		if claims, ok := ctx.Authorizer["claims"].(map[string]string); !ok || claims["cognito:groups"] != "admin" {
+			t.Errorf("Authorizer[claims] = %v", ctx.Authorizer["claims"])
+		}
+		if !reflect.DeepEqual(event.PathParameters, config.PathParameters) || !reflect.DeepEqual(event.StageVariables, config.StageVariables) {
+			t.Errorf("PathParameters = %v, StageVariables = %v", event.PathParameters, event.StageVariables)
+		}
+	})
+
+	t.Run("no authorizer", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "lambda://my-function/", nil)
+		event, err := httpRequestToLambdaEvent(req, LambdaConfig{})
+		if err != nil {
+			t.Fatalf("httpRequestToLambdaEvent() error = %v", err)
+		}
+		if event.RequestContext.Authorizer != nil {
+			t.Errorf("Authorizer = %+v, want nil", event.RequestContext.Authorizer)
+		}
+		if event.RequestContext.Stage != "$default" || event.RequestContext.HTTP.SourceIP != "127.0.0.1" {
+			t.Errorf("stage = %q, source IP = %q", event.RequestContext.Stage, event.RequestContext.HTTP.SourceIP)
+		}
+	})
+}
+
+func TestARNAccountID(t *testing.T) {
+	tests := map[string]string{
+		"arn:aws:iam::210987654321:user/alice":                       "210987654321",
+		"arn:aws:sts::210987654321:assumed-role/deploy/session-name": "210987654321",
+		"alice": "",
+	}
+	for arn, expected := range tests {
+		if got := arnAccountID(arn); got != expected {
+			t.Errorf("arnAccountID(%q) = %q, want %q", arn, got, expected)
+		}
+	}
+}
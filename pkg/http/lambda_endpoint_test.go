@@ -0,0 +1,255 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// invokeRecord is one Invoke API call received by fakeLambdaAPI
+type invokeRecord struct {
+	Function       string
+	Qualifier      string
+	InvocationType string
+	LogType        string
+	Authorization  string
+	Event          events.APIGatewayV2HTTPRequest
+}
+
+// fakeLambdaAPI emulates the Lambda Invoke API, as served by the Runtime
+// Interface Emulator and sam local start-lambda. Functions named "fails"
+// return an unhandled error; all others echo the request path and body.
+// Event and DryRun invocations are answered with 202 and 204, as by AWS.
+type fakeLambdaAPI struct {
+	mu      sync.Mutex
+	invokes []invokeRecord
+}
+
+func (f *fakeLambdaAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	function, ok := strings.CutPrefix(r.URL.Path, "/2015-03-31/functions/")
+	function, ok2 := strings.CutSuffix(function, "/invocations")
+	if !ok || !ok2 || r.Method != http.MethodPost {
+		http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+
+	record := invokeRecord{
+		Function:       function,
+		Qualifier:      r.URL.Query().Get("Qualifier"),
+		InvocationType: r.Header.Get("X-Amz-Invocation-Type"),
+		LogType:        r.Header.Get("X-Amz-Log-Type"),
+		Authorization:  r.Header.Get("Authorization"),
+	}
+	payload, _ := io.ReadAll(r.Body)
+	if err := json.Unmarshal(payload, &record.Event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.invokes = append(f.invokes, record)
+	f.mu.Unlock()
+
+	switch record.InvocationType {
+	case "Event":
+		w.WriteHeader(http.StatusAccepted)
+		return
+	case "DryRun":
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if record.LogType == "Tail" {
+		w.Header().Set("X-Amz-Log-Result", base64.StdEncoding.EncodeToString([]byte("START RequestId: 1\nEND RequestId: 1\n")))
+	}
+
+	if function == "fails" {
+		w.Header().Set("X-Amz-Function-Error", "Unhandled")
+		w.Write([]byte(`{"errorType":"Error","errorMessage":"boom","stackTrace":["at handler (index.js:1:1)"]}`))
+		return
+	}
+
+	json.NewEncoder(w).Encode(events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"Content-Type": "text/plain"},
+		Body:       record.Event.RawPath + " " + record.Event.Body,
+	})
+}
+
+// isolateAWSConfig hides the caller's AWS config so tests never reach AWS
+func isolateAWSConfig(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	for _, name := range []string{"AWS_PROFILE", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestLambdaEndpoint(t *testing.T) {
+	isolateAWSConfig(t)
+
+	api := &fakeLambdaAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	var logs strings.Builder
+	client, _ := NewClient()
+	client.Lambda = LambdaOptions{Endpoint: server.URL, LogWriter: &logs}
+
+	req, _ := http.NewRequest("POST", NormalizeLambdaURL("lambda://my-function:prod/users"), strings.NewReader("hello"))
+	req.Header.Set("Content-Type", "text/plain")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "/users hello" {
+		t.Errorf("response = %d %q, want 200 %q", resp.StatusCode, body, "/users hello")
+	}
+
+	if len(api.invokes) != 1 {
+		t.Fatalf("expected 1 invoke, got %d", len(api.invokes))
+	}
+	invoke := api.invokes[0]
+	if invoke.Function != "my-function" || invoke.Qualifier != "prod" {
+		t.Errorf("invoked %q qualifier %q, want my-function qualifier prod", invoke.Function, invoke.Qualifier)
+	}
+	if invoke.Authorization != "" {
+		t.Errorf("expected an unsigned request without credentials, got Authorization %q", invoke.Authorization)
+	}
+	if invoke.Event.RequestContext.HTTP.Method != "POST" {
+		t.Errorf("event method = %q", invoke.Event.RequestContext.HTTP.Method)
+	}
+	if !strings.Contains(logs.String(), "START RequestId") {
+		t.Errorf("expected the log tail to be written, got %q", logs.String())
+	}
+}
+
+func TestLambdaEndpoint_SignsWithCredentials(t *testing.T) {
+	isolateAWSConfig(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	api := &fakeLambdaAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.Lambda = LambdaOptions{Endpoint: server.URL}
+
+	resp, err := client.Get("lambda://my-function/")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(api.invokes) != 1 || !strings.Contains(api.invokes[0].Authorization, "Credential=AKIDEXAMPLE/") {
+		t.Errorf("expected a SigV4-signed invoke, got %+v", api.invokes)
+	}
+}
+
+func TestLambdaEndpoint_FunctionError(t *testing.T) {
+	isolateAWSConfig(t)
+
+	server := httptest.NewServer(&fakeLambdaAPI{})
+	defer server.Close()
+
+	client, _ := NewClient()
+	client.Lambda = LambdaOptions{Endpoint: server.URL}
+
+	_, err := client.Get("lambda://fails/")
+	fnErr, ok := err.(*LambdaFunctionError)
+	if !ok {
+		t.Fatalf("expected *LambdaFunctionError, got %T: %v", err, err)
+	}
+	if fnErr.Kind != "Unhandled" || fnErr.Message != "boom" || len(fnErr.StackTrace) != 1 {
+		t.Errorf("unexpected function error %+v", fnErr)
+	}
+}
+
+func TestLambdaEndpoint_InvocationTypes(t *testing.T) {
+	isolateAWSConfig(t)
+
+	tests := []struct {
+		invocationType types.InvocationType
+		status         int
+	}{
+		{types.InvocationTypeEvent, http.StatusAccepted},
+		{types.InvocationTypeDryRun, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.invocationType), func(t *testing.T) {
+			api := &fakeLambdaAPI{}
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			var logs strings.Builder
+			client, _ := NewClient()
+			client.Lambda = LambdaOptions{Endpoint: server.URL, InvocationType: tt.invocationType, LogWriter: &logs}
+
+			// A failing function must not matter: neither type waits for the result
+			resp, err := client.Post("lambda://fails/webhook", "application/json", strings.NewReader(`{}`))
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status || len(body) != 0 {
+				t.Errorf("response = %d %q, want %d with no body", resp.StatusCode, body, tt.status)
+			}
+			if got := resp.Header.Get("X-Amz-Invocation-Type"); got != string(tt.invocationType) {
+				t.Errorf("X-Amz-Invocation-Type = %q", got)
+			}
+			if len(api.invokes) != 1 || api.invokes[0].InvocationType != string(tt.invocationType) {
+				t.Fatalf("invokes = %+v", api.invokes)
+			}
+			if api.invokes[0].LogType != "" {
+				t.Errorf("LogType = %q, log tails are only available for RequestResponse", api.invokes[0].LogType)
+			}
+		})
+	}
+}
+
+func TestParseLambdaInvocationType(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected types.InvocationType
+		wantErr  bool
+	}{
+		{value: "", expected: types.InvocationTypeRequestResponse},
+		{value: "RequestResponse", expected: types.InvocationTypeRequestResponse},
+		{value: "event", expected: types.InvocationTypeEvent},
+		{value: "async", expected: types.InvocationTypeEvent},
+		{value: "DryRun", expected: types.InvocationTypeDryRun},
+		{value: "dry-run", expected: types.InvocationTypeDryRun},
+		{value: "stream", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			invocationType, err := ParseLambdaInvocationType(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLambdaInvocationType(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if invocationType != tt.expected {
+				t.Errorf("ParseLambdaInvocationType(%q) = %q, want %q", tt.value, invocationType, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LambdaFunctionError is returned when a Lambda function fails instead of
+// returning a proxy response. The fields come from the function's error payload.
+type LambdaFunctionError struct {
+	Target     LambdaTarget
+	Kind       string   // FunctionError from the invoke response: Unhandled or Handled
+	Type       string   // errorType, e.g. TypeError or Runtime.ExitError
+	Message    string   // errorMessage
+	StackTrace []string // stackTrace, one frame per line
+	Payload    []byte   // Raw error payload
+}
+
+// Error implements the error interface
+func (e *LambdaFunctionError) Error() string {
+	msg := fmt.Sprintf("Lambda function error (%s)", e.Kind)
+	switch {
+	case e.Type != "" && e.Message != "":
+		msg += fmt.Sprintf(": %s: %s", e.Type, e.Message)
+	case e.Message != "":
+		msg += ": " + e.Message
+	case e.Type != "":
+		msg += ": " + e.Type
+	}
+	return msg
+}
+
+// lambdaErrorPayload is the error document written by the Lambda runtimes
+type lambdaErrorPayload struct {
+	ErrorMessage string          `json:"errorMessage"`
+	ErrorType    string          `json:"errorType"`
+	StackTrace   json.RawMessage `json:"stackTrace"`
+}
+
+// parseLambdaFunctionError decodes a function error payload. Payloads that are
+// not the runtime's error document are kept raw in the message.
+func parseLambdaFunctionError(target LambdaTarget, kind string, payload []byte) *LambdaFunctionError {
+	fnErr := &LambdaFunctionError{Target: target, Kind: kind, Payload: payload}
+
+	var doc lambdaErrorPayload
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		fnErr.Message = strings.TrimSpace(string(payload))
+		return fnErr
+	}
+
+	fnErr.Type = doc.ErrorType
+	fnErr.Message = doc.ErrorMessage
+	fnErr.StackTrace = parseStackTrace(doc.StackTrace)
+	return fnErr
+}
+
+// parseStackTrace normalizes the stack trace formats of the runtimes: Node.js,
+// Python and Java send strings (Python sometimes nested lists), Go sends frames
+func parseStackTrace(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var strs []string
+	if err := json.Unmarshal(raw, &strs); err == nil {
+		return strs
+	}
+
+	var frames []json.RawMessage
+	if err := json.Unmarshal(raw, &frames); err != nil {
+		return []string{string(raw)}
+	}
+
+	lines := make([]string, 0, len(frames))
+
+	for _, frame := range frames {
+		var goFrame struct {
+			Path  string `json:"path"`
+			Line  int    `json:"line"`
+			Label string `json:"label"`
+		}
+		var parts []interface{}
+
+		switch {
+		case json.Unmarshal(frame, &goFrame) == nil && goFrame.Path != "":
+			lines = append(lines, fmt.Sprintf("%s (%s:%d)", goFrame.Label, goFrame.Path, goFrame.Line))
+		case json.Unmarshal(frame, &parts) == nil:
+			// Python: [file, line, function, code]
+			strs := make([]string, len(parts))
+			for i, part := range parts {
+				strs[i] = fmt.Sprint(part)
+			}
+			lines = append(lines, strings.Join(strs, ", "))
+		default:
+			lines = append(lines, string(frame))
+		}
+	}
+
+	return lines
+}
+
+// writeLambdaLogs decodes the base64 log tail from an invoke response and writes it to w
+func writeLambdaLogs(w io.Writer, logResult *string) error {
+	if w == nil || logResult == nil || *logResult == "" {
+		return nil
+	}
+
+	logs, err := base64.StdEncoding.DecodeString(*logResult)
+	if err != nil {
+		return fmt.Errorf("decoding Lambda log tail: %w", err)
+	}
+
+	if _, err := w.Write(logs); err != nil {
+		return err
+	}
+	if len(logs) > 0 && logs[len(logs)-1] != '\n' {
+		_, err = io.WriteString(w, "\n")
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestParseLambdaFunctionError(t *testing.T) {
+	target := LambdaTarget{FunctionName: "my-fn", Qualifier: "prod"}
+
+	tests := []struct {
+		name           string
+		payload        string
+		wantType       string
+		wantMessage    string
+		wantStackTrace []string
+		wantError      string
+	}{
+		{
+			name:           "Node.js",
+			payload:        `{"errorType": "TypeError", "errorMessage": "Cannot read properties of undefined", "stackTrace": ["TypeError: Cannot read properties of undefined", "    at handler (/var/task/index.js:3:20)"]}`,
+			wantType:       "TypeError",
+			wantMessage:    "Cannot read properties of undefined",
+			wantStackTrace: []string{"TypeError: Cannot read properties of undefined", "    at handler (/var/task/index.js:3:20)"},
+			wantError:      "Lambda function error (Unhandled): TypeError: Cannot read properties of undefined",
+		},
+		{
+			name:           "Python frames",
+			payload:        `{"errorType": "KeyError", "errorMessage": "'id'", "stackTrace": [["/var/task/app.py", 4, "handler", "return event['id']"]]}`,
+			wantType:       "KeyError",
+			wantMessage:    "'id'",
+			wantStackTrace: []string{"/var/task/app.py, 4, handler, return event['id']"},
+			wantError:      "Lambda function error (Unhandled): KeyError: 'id'",
+		},
+		{
+			name:           "Go frames",
+			payload:        `{"errorType": "errorString", "errorMessage": "boom", "stackTrace": [{"path": "main.go", "line": 12, "label": "handler"}]}`,
+			wantType:       "errorString",
+			wantMessage:    "boom",
+			wantStackTrace: []string{"handler (main.go:12)"},
+			wantError:      "Lambda function error (Unhandled): errorString: boom",
+		},
+		{
+			name:        "runtime timeout",
+			payload:     `{"errorMessage": "2024-01-01T00:00:00Z Task timed out after 3.00 seconds"}`,
+			wantMessage: "2024-01-01T00:00:00Z Task timed out after 3.00 seconds",
+			wantError:   "Lambda function error (Unhandled): 2024-01-01T00:00:00Z Task timed out after 3.00 seconds",
+		},
+		{
+			name:        "non-JSON payload",
+			payload:     "out of memory\n",
+			wantMessage: "out of memory",
+			wantError:   "Lambda function error (Unhandled): out of memory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fnErr := parseLambdaFunctionError(target, "Unhandled", []byte(tt.payload))
+
+			if fnErr.Target != target || fnErr.Kind != "Unhandled" {
+				t.Errorf("Unexpected target/kind: %+v %q", fnErr.Target, fnErr.Kind)
+			}
+			if fnErr.Type != tt.wantType {
+				t.Errorf("Expected type %q, got %q", tt.wantType, fnErr.Type)
+			}
+			if fnErr.Message != tt.wantMessage {
+				t.Errorf("Expected message %q, got %q", tt.wantMessage, fnErr.Message)
+			}
+			if !reflect.DeepEqual(fnErr.StackTrace, tt.wantStackTrace) {
+				t.Errorf("Expected stack trace %q, got %q", tt.wantStackTrace, fnErr.StackTrace)
+			}
+			if fnErr.Error() != tt.wantError {
+				t.Errorf("Expected error %q, got %q", tt.wantError, fnErr.Error())
+			}
+		})
+	}
+}
+
+func TestWriteLambdaLogs(t *testing.T) {
+	var buf bytes.Buffer
+	logs := base64.StdEncoding.EncodeToString([]byte("START RequestId: 1\nEND RequestId: 1"))
+
+	if err := writeLambdaLogs(&buf, aws.String(logs)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "START RequestId: 1\nEND RequestId: 1\n" {
+		t.Errorf("Unexpected logs %q", buf.String())
+	}
+
+	if err := writeLambdaLogs(&buf, aws.String("not base64!")); err == nil {
+		t.Error("Expected an error for an invalid log tail")
+	}
+
+	// Nothing is written without a writer or logs
+	if err := writeLambdaLogs(nil, aws.String(logs)); err != nil {
+		t.Errorf("Unexpected error without writer: %v", err)
+	}
+}
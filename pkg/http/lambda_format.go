@@ -0,0 +1,243 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// LambdaFormat selects the proxy event a Lambda function expects
+type LambdaFormat string
+
+const (
+	// LambdaFormatV2 is the API Gateway HTTP API (payload 2.0) event, the default
+	LambdaFormatV2 LambdaFormat = "v2"
+	// LambdaFormatV1 is the API Gateway REST API (payload 1.0) event
+	LambdaFormatV1 LambdaFormat = "v1"
+	// LambdaFormatALB is the Application Load Balancer target group event
+	LambdaFormatALB LambdaFormat = "alb"
+)
+
+// LambdaFormats lists the supported event formats
+var LambdaFormats = []LambdaFormat{LambdaFormatV2, LambdaFormatV1, LambdaFormatALB}
+
+// ParseLambdaFormat parses a --lambda-format value. An empty value selects v2.
+func ParseLambdaFormat(value string) (LambdaFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "v2", "2.0", "http":
+		return LambdaFormatV2, nil
+	case "v1", "1.0", "rest":
+		return LambdaFormatV1, nil
+	case "alb":
+		return LambdaFormatALB, nil
+	}
+	return "", fmt.Errorf("unknown Lambda event format %q (supported: v2, v1, alb)", value)
+}
+
+// LambdaOptions configures direct Lambda invocation
+type LambdaOptions struct {
+	// Format is the event format used for plain lambda:// URLs.
+	// Schemes such as lambda+v1:// and lambda+alb:// override it per request.
+	Format LambdaFormat
+
+	// LogWriter receives the last 4KB of the function's logs for each
+	// invocation. Logs are only requested when it is set.
+	LogWriter io.Writer
+
+	// InvocationType selects synchronous (RequestResponse, the default),
+	// asynchronous (Event) or permission-checking (DryRun) invocation.
+	// Event and DryRun return a synthetic 202 or 204 response without a body.
+	InvocationType types.InvocationType
+
+	// Stream invokes functions with InvokeWithResponseStream, so the body of
+	// a streaming function is read as it is produced. It applies to
+	// RequestResponse invocations only.
+	Stream bool
+
+	// Endpoint overrides the Lambda API endpoint, e.g. http://localhost:3001
+	// for sam local start-lambda or http://localhost:9000 for the Runtime
+	// Interface Emulator.
+	Endpoint string
+
+	// Event is the request context of the synthetic events: stage, source IP,
+	// stage variables, path parameters and authorizer output. Empty fields
+	// use DefaultLambdaConfig.
+	Event LambdaConfig
+}
+
+// IsLambdaURL reports whether a URL uses one of the lambda schemes
+// (lambda://, lambda+v1://, lambda+v2://, lambda+alb://)
+func IsLambdaURL(rawURL string) bool {
+	scheme, _, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return false
+	}
+	_, ok = lambdaSchemeFormat(strings.ToLower(scheme))
+	return ok
+}
+
+// lambdaSchemeFormat returns the event format selected by a URL scheme.
+// The format is empty for plain lambda://, which uses the client default.
+func lambdaSchemeFormat(scheme string) (LambdaFormat, bool) {
+	if scheme == "lambda" {
+		return "", true
+	}
+
+	suffix, ok := strings.CutPrefix(scheme, "lambda+")
+	if !ok {
+		return "", false
+	}
+
+	format, err := ParseLambdaFormat(suffix)
+	if err != nil || suffix == "" {
+		return "", false
+	}
+	return format, true
+}
+
+// lambdaEvent converts an http.Request to the proxy event for format
+func lambdaEvent(req *http.Request, format LambdaFormat, config LambdaConfig) (interface{}, error) {
+	switch format {
+	case LambdaFormatV1:
+		return httpRequestToV1Event(req, config)
+	case LambdaFormatALB:
+		return httpRequestToALBEvent(req, config)
+	default:
+		return httpRequestToLambdaEvent(req, config)
+	}
+}
+
+// httpRequestToV1Event converts an http.Request to an API Gateway REST API (v1) proxy event
+func httpRequestToV1Event(req *http.Request, config LambdaConfig) (*events.APIGatewayProxyRequest, error) {
+	body, isBase64Encoded, err := lambdaRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, multiValueHeaders := lambdaHeaders(req)
+	query, multiValueQuery := lambdaQuery(req.URL)
+
+	config = config.withDefaults()
+	now := time.Now()
+
+	return &events.APIGatewayProxyRequest{
+		Resource:                        req.URL.Path,
+		Path:                            req.URL.Path,
+		HTTPMethod:                      req.Method,
+		Headers:                         headers,
+		MultiValueHeaders:               multiValueHeaders,
+		QueryStringParameters:           query,
+		MultiValueQueryStringParameters: multiValueQuery,
+		PathParameters:                  config.PathParameters,
+		StageVariables:                  config.StageVariables,
+		RequestContext: events.APIGatewayProxyRequestContext{
+			AccountID:        config.AccountID,
+			APIID:            config.APIID,
+			DomainName:       config.DomainName,
+			DomainPrefix:     config.DomainPrefix,
+			Stage:            config.Stage,
+			RequestID:        fmt.Sprintf("qurl-%d", now.UnixNano()),
+			ResourcePath:     req.URL.Path,
+			Path:             req.URL.Path,
+			HTTPMethod:       req.Method,
+			Protocol:         "HTTP/1.1",
+			RequestTime:      now.Format("02/Jan/2006:15:04:05 -0700"),
+			RequestTimeEpoch: now.UnixMilli(),
+			Identity: config.Authorizer.v1Identity(events.APIGatewayRequestIdentity{
+				SourceIP:  config.SourceIP,
+				UserAgent: config.UserAgent,
+			}),
+			Authorizer: config.Authorizer.v1Authorizer(),
+		},
+		Body:            body,
+		IsBase64Encoded: isBase64Encoded,
+	}, nil
+}
+
+// httpRequestToALBEvent converts an http.Request to an ALB target group event.
+// The event uses multi-value headers and query parameters, as an ALB does when
+// multi-value headers are enabled on the target group. An ALB has no stage or
+// authorizer, so only the account ID is taken from config.
+func httpRequestToALBEvent(req *http.Request, config LambdaConfig) (*events.ALBTargetGroupRequest, error) {
+	body, isBase64Encoded, err := lambdaRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, multiValueHeaders := lambdaHeaders(req)
+	_, multiValueQuery := lambdaQuery(req.URL)
+
+	config = config.withDefaults()
+
+	return &events.ALBTargetGroupRequest{
+		HTTPMethod:                      req.Method,
+		Path:                            req.URL.Path,
+		MultiValueHeaders:               multiValueHeaders,
+		MultiValueQueryStringParameters: multiValueQuery,
+		RequestContext: events.ALBTargetGroupRequestContext{
+			ELB: events.ELBContext{
+				TargetGroupArn: fmt.Sprintf("arn:aws:elasticloadbalancing:us-east-1:%s:targetgroup/%s/0000000000000000", config.AccountID, config.APIID),
+			},
+		},
+		Body:            body,
+		IsBase64Encoded: isBase64Encoded,
+	}, nil
+}
+
+// lambdaHeaders returns the request headers, including Host, as single-value
+// (comma-joined) and multi-value maps
+func lambdaHeaders(req *http.Request) (map[string]string, map[string][]string) {
+	headers := make(map[string]string)
+	multiValueHeaders := make(map[string][]string)
+	for key, values := range req.Header {
+		headers[key] = strings.Join(values, ",")
+		multiValueHeaders[key] = append([]string{}, values...)
+	}
+
+	// Add Host header from req.Host (it's not in req.Header in Go's http package)
+	if req.Host != "" {
+		headers["Host"] = req.Host
+		multiValueHeaders["Host"] = []string{req.Host}
+	}
+
+	return headers, multiValueHeaders
+}
+
+// lambdaQuery returns the query parameters as single-value (last value wins,
+// as in API Gateway v1) and multi-value maps
+func lambdaQuery(u *url.URL) (map[string]string, map[string][]string) {
+	query := make(map[string]string)
+	multiValueQuery := make(map[string][]string)
+	for key, values := range u.Query() {
+		query[key] = values[len(values)-1]
+		multiValueQuery[key] = values
+	}
+	return query, multiValueQuery
+}
+
+// lambdaResponseToHTTPFormat converts a Lambda response in the given format to an http.Response
+func lambdaResponseToHTTPFormat(payload []byte, format LambdaFormat) (*http.Response, error) {
+	switch format {
+	case LambdaFormatV1:
+		var lambdaResp events.APIGatewayProxyResponse
+		if err := json.Unmarshal(payload, &lambdaResp); err != nil {
+			return nil, fmt.Errorf("parsing Lambda response: %w", err)
+		}
+		return newLambdaHTTPResponse(lambdaResp.StatusCode, "", lambdaResp.Headers, lambdaResp.MultiValueHeaders, lambdaResp.Body, lambdaResp.IsBase64Encoded)
+	case LambdaFormatALB:
+		var lambdaResp events.ALBTargetGroupResponse
+		if err := json.Unmarshal(payload, &lambdaResp); err != nil {
+			return nil, fmt.Errorf("parsing Lambda response: %w", err)
+		}
+		return newLambdaHTTPResponse(lambdaResp.StatusCode, lambdaResp.StatusDescription, lambdaResp.Headers, lambdaResp.MultiValueHeaders, lambdaResp.Body, lambdaResp.IsBase64Encoded)
+	default:
+		return lambdaResponseToHTTP(payload)
+	}
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLambdaFormat(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected LambdaFormat
+		wantErr  bool
+	}{
+		{value: "", expected: LambdaFormatV2},
+		{value: "v2", expected: LambdaFormatV2},
+		{value: "V1", expected: LambdaFormatV1},
+		{value: "rest", expected: LambdaFormatV1},
+		{value: "alb", expected: LambdaFormatALB},
+		{value: "sqs", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			format, err := ParseLambdaFormat(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLambdaFormat(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if format != tt.expected {
+				t.Errorf("ParseLambdaFormat(%q) = %q, want %q", tt.value, format, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsLambdaURL(t *testing.T) {
+	tests := map[string]bool{
+		"lambda://fn/path":     true,
+		"lambda+v1://fn/path":  true,
+		"lambda+alb://fn/path": true,
+		"LAMBDA+V2://fn/path":  true,
+		"lambda+sqs://fn/path": false,
+		"lambda+://fn/path":    false,
+		"https://example.com/": false,
+		"/lambda://not/a/url":  false,
+		"lambdas://fn/path":    false,
+	}
+
+	for url, expected := range tests {
+		if got := IsLambdaURL(url); got != expected {
+			t.Errorf("IsLambdaURL(%q) = %v, want %v", url, got, expected)
+		}
+	}
+}
+
+func TestHTTPRequestToV1Event(t *testing.T) {
+	req, _ := http.NewRequest("POST", "lambda+v1://my-function/users?tag=a&tag=b&limit=10", strings.NewReader(`{"name":"test"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Add("X-Forwarded-For", "10.0.0.1")
+	req.Header.Add("X-Forwarded-For", "10.0.0.2")
+
+	event, err := httpRequestToV1Event(req, DefaultLambdaConfig())
+	if err != nil {
+		t.Fatalf("httpRequestToV1Event() error = %v", err)
+	}
+
+	if event.HTTPMethod != "POST" || event.Path != "/users" || event.RequestContext.HTTPMethod != "POST" {
+		t.Errorf("Unexpected method/path: %s %s", event.HTTPMethod, event.Path)
+	}
+	if !reflect.DeepEqual(event.MultiValueHeaders["X-Forwarded-For"], []string{"10.0.0.1", "10.0.0.2"}) {
+		t.Errorf("Expected multi-value header, got %v", event.MultiValueHeaders["X-Forwarded-For"])
+	}
+	if event.Headers["Host"] != "my-function" {
+		t.Errorf("Expected Host header my-function, got %q", event.Headers["Host"])
+	}
+	if !reflect.DeepEqual(event.MultiValueQueryStringParameters["tag"], []string{"a", "b"}) {
+		t.Errorf("Expected multi-value query, got %v", event.MultiValueQueryStringParameters["tag"])
+	}
+	if event.QueryStringParameters["tag"] != "b" {
+		t.Errorf("Expected last query value to win, got %q", event.QueryStringParameters["tag"])
+	}
+	if event.Body != `{"name":"test"}` || event.IsBase64Encoded {
+		t.Errorf("Unexpected body %q (base64 %v)", event.Body, event.IsBase64Encoded)
+	}
+}
+
+func TestHTTPRequestToALBEvent(t *testing.T) {
+	req, _ := http.NewRequest("GET", "lambda+alb://my-function/users?tag=a&tag=b", nil)
+	req.Header.Add("Accept", "application/json")
+
+	event, err := httpRequestToALBEvent(req, DefaultLambdaConfig())
+	if err != nil {
+		t.Fatalf("httpRequestToALBEvent() error = %v", err)
+	}
+
+	if event.HTTPMethod != "GET" || event.Path != "/users" {
+		t.Errorf("Unexpected method/path: %s %s", event.HTTPMethod, event.Path)
+	}
+	if event.RequestContext.ELB.TargetGroupArn == "" {
+		t.Error("Expected a target group ARN in the request context")
+	}
+	if len(event.Headers) != 0 || len(event.QueryStringParameters) != 0 {
+		t.Error("Expected only multi-value headers and query parameters")
+	}
+	if !reflect.DeepEqual(event.MultiValueQueryStringParameters["tag"], []string{"a", "b"}) {
+		t.Errorf("Expected multi-value query, got %v", event.MultiValueQueryStringParameters["tag"])
+	}
+	if !reflect.DeepEqual(event.MultiValueHeaders["Accept"], []string{"application/json"}) {
+		t.Errorf("Expected Accept header, got %v", event.MultiValueHeaders["Accept"])
+	}
+}
+
+func TestLambdaResponseToHTTPFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      LambdaFormat
+		payload     string
+		wantStatus  string
+		wantCookies []string
+		wantBody    string
+	}{
+		{
+			name:        "v2 with cookies",
+			format:      LambdaFormatV2,
+			payload:     `{"statusCode": 201, "headers": {"Content-Type": "text/plain"}, "cookies": ["a=1", "b=2"], "body": "created"}`,
+			wantStatus:  "201 Created",
+			wantCookies: []string{"a=1", "b=2"},
+			wantBody:    "created",
+		},
+		{
+			name:        "v1 with multi-value headers",
+			format:      LambdaFormatV1,
+			payload:     `{"statusCode": 200, "headers": {"Set-Cookie": "ignored=1"}, "multiValueHeaders": {"Set-Cookie": ["a=1", "b=2"]}, "body": "aGVsbG8=", "isBase64Encoded": true}`,
+			wantStatus:  "200 OK",
+			wantCookies: []string{"a=1", "b=2"},
+			wantBody:    "hello",
+		},
+		{
+			name:       "ALB with status description",
+			format:     LambdaFormatALB,
+			payload:    `{"statusCode": 404, "statusDescription": "404 Not Found", "multiValueHeaders": {"Content-Type": ["application/json"]}, "body": "{}"}`,
+			wantStatus: "404 Not Found",
+			wantBody:   "{}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := lambdaResponseToHTTPFormat([]byte(tt.payload), tt.format)
+			if err != nil {
+				t.Fatalf("lambdaResponseToHTTPFormat() error = %v", err)
+			}
+
+			if resp.Status != tt.wantStatus {
+				t.Errorf("Expected status %q, got %q", tt.wantStatus, resp.Status)
+			}
+			if cookies := resp.Header.Values("Set-Cookie"); !reflect.DeepEqual(cookies, tt.wantCookies) && len(tt.wantCookies) > 0 {
+				t.Errorf("Expected cookies %v, got %v", tt.wantCookies, cookies)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
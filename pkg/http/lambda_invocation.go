@@ -0,0 +1,59 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// LambdaInvocationTypes lists the supported invocation types
+var LambdaInvocationTypes = []types.InvocationType{
+	types.InvocationTypeRequestResponse,
+	types.InvocationTypeEvent,
+	types.InvocationTypeDryRun,
+}
+
+// ParseLambdaInvocationType parses a --lambda-invocation-type value.
+// An empty value selects RequestResponse.
+func ParseLambdaInvocationType(value string) (types.InvocationType, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "requestresponse", "request-response", "sync":
+		return types.InvocationTypeRequestResponse, nil
+	case "event", "async":
+		return types.InvocationTypeEvent, nil
+	case "dryrun", "dry-run":
+		return types.InvocationTypeDryRun, nil
+	}
+	return "", fmt.Errorf("unknown Lambda invocation type %q (supported: RequestResponse, Event, DryRun)", value)
+}
+
+// lambdaInvocationResponse builds the response for Event and DryRun
+// invocations, which return no payload: 202 Accepted once the event is
+// queued, 204 No Content once the caller's permissions are verified
+func lambdaInvocationResponse(invocationType types.InvocationType, output *lambda.InvokeOutput) *http.Response {
+	statusCode := int(output.StatusCode)
+	if statusCode == 0 {
+		statusCode = http.StatusAccepted
+		if invocationType == types.InvocationTypeDryRun {
+			statusCode = http.StatusNoContent
+		}
+	}
+
+	resp := &http.Response{
+		StatusCode: statusCode,
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Header:     make(http.Header),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Body:       http.NoBody,
+	}
+	resp.Header.Set("X-Amz-Invocation-Type", string(invocationType))
+	if output.ExecutedVersion != nil {
+		resp.Header.Set("X-Amz-Executed-Version", *output.ExecutedVersion)
+	}
+	return resp
+}
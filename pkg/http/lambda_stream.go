@@ -0,0 +1,212 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// lambdaStreamDelimiter separates the HTTP integration prelude from the body
+// in a streamed response, as written by awslambda.HttpResponseStream
+var lambdaStreamDelimiter = make([]byte, 8)
+
+// maxLambdaStreamPrelude bounds how much of a stream is buffered while looking
+// for the prelude; streams without one are passed through as the body
+const maxLambdaStreamPrelude = 64 * 1024
+
+// lambdaStreamPrelude is the status and headers a streaming function sends
+// before its body
+type lambdaStreamPrelude struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers"`
+	Cookies    []string          `json:"cookies"`
+}
+
+// invokeStream invokes a function with InvokeWithResponseStream. The status and
+// headers come from the HTTP integration prelude; the body is read from the
+// event stream as chunks arrive.
+func (c *Client) invokeStream(ctx context.Context, req *http.Request, target LambdaTarget, input *lambda.InvokeInput, format LambdaFormat, optFns ...func(*lambda.Options)) (*http.Response, error) {
+	output, err := c.lambdaClient.InvokeWithResponseStream(ctx, &lambda.InvokeWithResponseStreamInput{
+		FunctionName:   input.FunctionName,
+		Qualifier:      input.Qualifier,
+		Payload:        input.Payload,
+		LogType:        input.LogType,
+		InvocationType: types.ResponseStreamingInvocationTypeRequestResponse,
+	}, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("invoking Lambda function: %w", err)
+	}
+
+	body := &lambdaStreamBody{
+		stream:    output.GetStream(),
+		target:    target,
+		logWriter: c.Lambda.LogWriter,
+	}
+
+	resp, err := lambdaStreamResponse(body, aws.ToString(output.ResponseStreamContentType), format)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	if output.ExecutedVersion != nil {
+		resp.Header.Set("X-Amz-Executed-Version", *output.ExecutedVersion)
+	}
+	resp.Request = req
+	return resp, nil
+}
+
+// lambdaStreamResponse reads the prelude from a streamed response and returns
+// a response whose body continues the stream. Function errors raised before
+// the prelude is complete are returned as errors.
+func lambdaStreamResponse(body *lambdaStreamBody, contentType string, format LambdaFormat) (*http.Response, error) {
+	var buffered []byte
+	chunk := make([]byte, 32*1024)
+	for {
+		if i := bytes.Index(buffered, lambdaStreamDelimiter); i >= 0 {
+			var prelude lambdaStreamPrelude
+			if err := json.Unmarshal(buffered[:i], &prelude); err == nil {
+				return newLambdaStreamResponse(prelude, io.MultiReader(bytes.NewReader(buffered[i+len(lambdaStreamDelimiter):]), body), body), nil
+			}
+			break
+		}
+		// Preludes and buffered proxy responses are JSON objects; anything
+		// else is streamed through without waiting for more
+		if trimmed := bytes.TrimLeft(buffered, " \t\r\n"); len(trimmed) > 0 && trimmed[0] != '{' {
+			break
+		}
+		if len(buffered) > maxLambdaStreamPrelude {
+			break
+		}
+
+		n, err := body.Read(chunk)
+		buffered = append(buffered, chunk[:n]...)
+		if err == io.EOF {
+			// A function that does not stream returns its whole proxy response
+			if resp, ok := bufferedLambdaStreamResponse(buffered, format); ok {
+				return resp, nil
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// No prelude: the stream is the body
+	prelude := lambdaStreamPrelude{StatusCode: http.StatusOK}
+	if contentType != "" && !strings.HasPrefix(contentType, "application/vnd.amazon.eventstream") &&
+		!strings.HasPrefix(contentType, "application/vnd.awslambda.http-integration-response") {
+		prelude.Headers = map[string]string{"Content-Type": contentType}
+	}
+	return newLambdaStreamResponse(prelude, io.MultiReader(bytes.NewReader(buffered), body), body), nil
+}
+
+// bufferedLambdaStreamResponse converts a complete proxy response received
+// over a stream, reporting false if the payload is not one
+func bufferedLambdaStreamResponse(payload []byte, format LambdaFormat) (*http.Response, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, false
+	}
+	if _, ok := fields["statusCode"]; !ok {
+		return nil, false
+	}
+
+	resp, err := lambdaResponseToHTTPFormat(payload, format)
+	return resp, err == nil
+}
+
+// newLambdaStreamResponse builds a response of unknown length from a prelude
+func newLambdaStreamResponse(prelude lambdaStreamPrelude, body io.Reader, closer io.Closer) *http.Response {
+	statusCode := prelude.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	resp := &http.Response{
+		StatusCode:    statusCode,
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Header:        make(http.Header),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		ContentLength: -1,
+		Body:          readCloser{Reader: body, Closer: closer},
+	}
+	for key, value := range prelude.Headers {
+		resp.Header.Set(key, value)
+	}
+	for _, cookie := range prelude.Cookies {
+		resp.Header.Add("Set-Cookie", cookie)
+	}
+	return resp
+}
+
+// readCloser joins a reader with the closer of the stream it reads from
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// lambdaStreamBody reads the payload chunks of a response stream. The stream
+// ends at the InvokeComplete event, which carries the log tail and any error
+// the function raised while streaming.
+type lambdaStreamBody struct {
+	stream    *lambda.InvokeWithResponseStreamEventStream
+	target    LambdaTarget
+	logWriter io.Writer
+	pending   []byte
+	err       error
+}
+
+// Read implements io.Reader
+func (b *lambdaStreamBody) Read(p []byte) (int, error) {
+	for len(b.pending) == 0 {
+		if b.err != nil {
+			return 0, b.err
+		}
+		b.next()
+	}
+
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+// next receives the next event from the stream
+func (b *lambdaStreamBody) next() {
+	event, ok := <-b.stream.Events()
+	if !ok {
+		b.err = b.stream.Err()
+		if b.err == nil {
+			b.err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+
+	switch e := event.(type) {
+	case *types.InvokeWithResponseStreamResponseEventMemberPayloadChunk:
+		b.pending = e.Value.Payload
+	case *types.InvokeWithResponseStreamResponseEventMemberInvokeComplete:
+		b.err = io.EOF
+		if err := writeLambdaLogs(b.logWriter, e.Value.LogResult); err != nil {
+			b.err = err
+		}
+		if code := aws.ToString(e.Value.ErrorCode); code != "" {
+			b.err = parseLambdaFunctionError(b.target, code, []byte(aws.ToString(e.Value.ErrorDetails)))
+		}
+	}
+}
+
+// Close implements io.Closer
+func (b *lambdaStreamBody) Close() error {
+	return b.stream.Close()
+}
@@ -0,0 +1,230 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream"
+)
+
+// streamEvent is one event written by fakeStreamingLambda: a payload chunk,
+// or InvokeComplete when complete is set
+type streamEvent struct {
+	chunk    string
+	complete bool
+	errCode  string
+	errBody  string
+	wait     chan struct{} // Blocks before the event is sent, if set
+}
+
+// fakeStreamingLambda emulates the InvokeWithResponseStream API, writing
+// events as an application/vnd.amazon.eventstream response
+func fakeStreamingLambda(t *testing.T, events []streamEvent) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/response-streaming-invocations") {
+			http.Error(w, "unexpected path "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		w.Header().Set("X-Amz-Executed-Version", "7")
+		w.WriteHeader(http.StatusOK)
+
+		encoder := eventstream.NewEncoder()
+		for _, event := range events {
+			if event.wait != nil {
+				<-event.wait
+			}
+
+			msg := eventstream.Message{Headers: eventstream.Headers{
+				{Name: ":message-type", Value: eventstream.StringValue("event")},
+			}}
+			if event.complete {
+				payload, _ := json.Marshal(map[string]string{"ErrorCode": event.errCode, "ErrorDetails": event.errBody})
+				if event.errCode == "" {
+					payload = []byte(`{}`)
+				}
+				msg.Headers = append(msg.Headers,
+					eventstream.Header{Name: ":event-type", Value: eventstream.StringValue("InvokeComplete")},
+					eventstream.Header{Name: ":content-type", Value: eventstream.StringValue("application/json")})
+				msg.Payload = payload
+			} else {
+				msg.Headers = append(msg.Headers,
+					eventstream.Header{Name: ":event-type", Value: eventstream.StringValue("PayloadChunk")},
+					eventstream.Header{Name: ":content-type", Value: eventstream.StringValue("application/octet-stream")})
+				msg.Payload = []byte(event.chunk)
+			}
+
+			if err := encoder.Encode(w, msg); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+}
+
+// streamingClient returns a client that streams responses from server
+func streamingClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	isolateAWSConfig(t)
+	client, _ := NewClient()
+	client.Lambda = LambdaOptions{Endpoint: server.URL, Stream: true}
+	return client
+}
+
+func TestLambdaStream_Incremental(t *testing.T) {
+	release := make(chan struct{})
+	prelude := `{"statusCode":201,"headers":{"Content-Type":"text/plain"},"cookies":["a=1"]}`
+	server := fakeStreamingLambda(t, []streamEvent{
+		{chunk: prelude[:20]},
+		{chunk: prelude[20:] + "\x00\x00\x00\x00\x00\x00\x00\x00first "},
+		{chunk: "second", wait: release},
+		{complete: true},
+	})
+	defer server.Close()
+	defer close(release)
+
+	resp, err := streamingClient(t, server).Get("lambda://streamer/events")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("response = %d %v", resp.StatusCode, resp.Header)
+	}
+	if resp.Header.Get("Set-Cookie") != "a=1" || resp.Header.Get("X-Amz-Executed-Version") != "7" {
+		t.Errorf("headers = %v", resp.Header)
+	}
+
+	// The first chunk must be readable before the function sends the second
+	buf := make([]byte, 64)
+	n, err := resp.Body.Read(buf)
+	if err != nil || string(buf[:n]) != "first " {
+		t.Fatalf("first read = %q, %v", buf[:n], err)
+	}
+
+	release <- struct{}{}
+	rest, err := io.ReadAll(resp.Body)
+	if err != nil || string(rest) != "second" {
+		t.Errorf("rest = %q, %v", rest, err)
+	}
+}
+
+func TestLambdaStream_IncrementalWithoutPrelude(t *testing.T) {
+	release := make(chan struct{})
+	server := fakeStreamingLambda(t, []streamEvent{
+		{chunk: "data: 1\n\n"},
+		{chunk: "data: 2\n\n", wait: release},
+		{complete: true},
+	})
+	defer server.Close()
+	defer close(release)
+
+	resp, err := streamingClient(t, server).Get("lambda://streamer/events")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	buf := make([]byte, 64)
+	n, err := resp.Body.Read(buf)
+	if err != nil || string(buf[:n]) != "data: 1\n\n" {
+		t.Fatalf("first read = %q, %v", buf[:n], err)
+	}
+	release <- struct{}{}
+}
+
+func TestLambdaStream_Responses(t *testing.T) {
+	tests := []struct {
+		name        string
+		events      []streamEvent
+		status      int
+		body        string
+		contentType string
+	}{
+		{
+			name:   "stream without prelude",
+			events: []streamEvent{{chunk: "data: 1\n\n"}, {chunk: "data: 2\n\n"}, {complete: true}},
+			status: http.StatusOK,
+			body:   "data: 1\n\ndata: 2\n\n",
+		},
+		{
+			name:        "buffered proxy response",
+			events:      []streamEvent{{chunk: `{"statusCode":404,"headers":{"Content-Type":"application/json"},"body":"{\"error\":\"missing\"}"}`}, {complete: true}},
+			status:      http.StatusNotFound,
+			body:        `{"error":"missing"}`,
+			contentType: "application/json",
+		},
+		{
+			name:   "empty body",
+			events: []streamEvent{{chunk: `{"statusCode":204}` + "\x00\x00\x00\x00\x00\x00\x00\x00"}, {complete: true}},
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakeStreamingLambda(t, tt.events)
+			defer server.Close()
+
+			resp, err := streamingClient(t, server).Get("lambda://streamer/")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("reading body: %v", err)
+			}
+			if resp.StatusCode != tt.status || string(body) != tt.body {
+				t.Errorf("response = %d %q, want %d %q", resp.StatusCode, body, tt.status, tt.body)
+			}
+			if got := resp.Header.Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestLambdaStream_FunctionErrors(t *testing.T) {
+	errBody := `{"errorType":"Error","errorMessage":"boom"}`
+
+	t.Run("before the prelude", func(t *testing.T) {
+		server := fakeStreamingLambda(t, []streamEvent{{complete: true, errCode: "Unhandled", errBody: errBody}})
+		defer server.Close()
+
+		_, err := streamingClient(t, server).Get("lambda://streamer/")
+		var fnErr *LambdaFunctionError
+		if !errors.As(err, &fnErr) || fnErr.Message != "boom" {
+			t.Fatalf("expected a function error, got %v", err)
+		}
+	})
+
+	t.Run("while streaming", func(t *testing.T) {
+		server := fakeStreamingLambda(t, []streamEvent{
+			{chunk: `{"statusCode":200}` + "\x00\x00\x00\x00\x00\x00\x00\x00partial"},
+			{complete: true, errCode: "Unhandled", errBody: errBody},
+		})
+		defer server.Close()
+
+		resp, err := streamingClient(t, server).Get("lambda://streamer/")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		var fnErr *LambdaFunctionError
+		if string(body) != "partial" || !errors.As(err, &fnErr) {
+			t.Errorf("body = %q, err = %v; want the partial body and a function error", body, err)
+		}
+	})
+}
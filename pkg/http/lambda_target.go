@@ -0,0 +1,160 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// LambdaTarget identifies the function version a lambda:// URL invokes.
+//
+// The function may be given as a name, a partial ARN or a full ARN, with an
+// optional alias or version after @ or a colon:
+//
+//	lambda://my-fn/path
+//	lambda://my-fn@prod/path
+//	lambda://my-fn:7/path
+//	lambda://123456789012:function:my-fn@prod/path
+//	lambda://arn:aws:lambda:eu-west-1:123456789012:function:my-fn:prod/path
+//
+// URLs with colons in the function are not valid for net/url, so they are
+// rewritten by NormalizeLambdaURL before a request is built.
+type LambdaTarget struct {
+	FunctionName string // Name, partial ARN or full ARN, without the qualifier
+	Qualifier    string // Alias or version, empty for the unqualified function
+	Region       string // Region from a full ARN, empty otherwise
+}
+
+// Name returns the short function name
+func (t LambdaTarget) Name() string {
+	if i := strings.LastIndex(t.FunctionName, ":"); i >= 0 {
+		return t.FunctionName[i+1:]
+	}
+	return t.FunctionName
+}
+
+// String describes the target for verbose output
+func (t LambdaTarget) String() string {
+	var details []string
+	if t.Qualifier != "" {
+		details = append(details, "qualifier: "+t.Qualifier)
+	}
+	if t.Region != "" {
+		details = append(details, "region: "+t.Region)
+	}
+	if len(details) == 0 {
+		return t.FunctionName
+	}
+	return fmt.Sprintf("%s (%s)", t.FunctionName, strings.Join(details, ", "))
+}
+
+// ParseLambdaTarget extracts the invocation target from a lambda:// URL
+func ParseLambdaTarget(u *url.URL) (LambdaTarget, error) {
+	function := u.Host
+	qualifier := ""
+	if u.User != nil {
+		// function@qualifier, where url.Parse splits an ARN at its first colon
+		function = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			function += ":" + password
+		}
+		qualifier = u.Host
+	}
+
+	if function == "" {
+		return LambdaTarget{}, fmt.Errorf("lambda URL missing function name")
+	}
+
+	target, err := parseLambdaFunction(function)
+	if err != nil {
+		return LambdaTarget{}, err
+	}
+
+	if qualifier != "" {
+		if target.Qualifier != "" && target.Qualifier != qualifier {
+			return LambdaTarget{}, fmt.Errorf("lambda URL has two qualifiers: %q and %q", target.Qualifier, qualifier)
+		}
+		target.Qualifier = qualifier
+	}
+
+	return target, nil
+}
+
+// NormalizeLambdaURL rewrites a lambda URL whose function contains colons
+// (ARNs and name:qualifier) to the parseable form function@qualifier, with the
+// function in the URL's user info. Other URLs are returned unchanged.
+func NormalizeLambdaURL(rawURL string) string {
+	if !IsLambdaURL(rawURL) {
+		return rawURL
+	}
+
+	scheme, rest, _ := strings.Cut(rawURL, "://")
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	authority, remainder := rest[:end], rest[end:]
+
+	if !strings.Contains(authority, ":") {
+		return rawURL
+	}
+
+	function, qualifier := authority, ""
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		function, qualifier = authority[:i], authority[i+1:]
+	}
+
+	target, err := parseLambdaFunction(function)
+	if err != nil || (qualifier != "" && target.Qualifier != "" && qualifier != target.Qualifier) {
+		// Leave it for ParseLambdaTarget or url.Parse to report
+		return rawURL
+	}
+	if qualifier == "" {
+		qualifier = target.Qualifier
+	}
+	if qualifier == "" {
+		// The host must not be empty; $LATEST is the unqualified function
+		qualifier = "$LATEST"
+	}
+
+	return scheme + "://" + url.User(target.FunctionName).String() + "@" + qualifier + remainder
+}
+
+// parseLambdaFunction splits a function name, partial ARN or full ARN into
+// the function and its qualifier, taking the region from a full ARN
+func parseLambdaFunction(function string) (LambdaTarget, error) {
+	parts := strings.Split(function, ":")
+
+	if parts[0] == "arn" {
+		// arn:partition:lambda:region:account:function:name[:qualifier]
+		if (len(parts) != 7 && len(parts) != 8) || parts[2] != "lambda" || parts[5] != "function" || parts[6] == "" {
+			return LambdaTarget{}, fmt.Errorf("invalid Lambda function ARN %q (expected arn:aws:lambda:region:account:function:name[:qualifier])", function)
+		}
+		target := LambdaTarget{FunctionName: strings.Join(parts[:7], ":"), Region: parts[3]}
+		if len(parts) == 8 {
+			target.Qualifier = parts[7]
+		}
+		return target, nil
+	}
+
+	switch len(parts) {
+	case 1:
+		return LambdaTarget{FunctionName: function}, nil
+	case 2:
+		// name:qualifier
+		if parts[0] != "" && parts[1] != "" {
+			return LambdaTarget{FunctionName: parts[0], Qualifier: parts[1]}, nil
+		}
+	case 3, 4:
+		// account:function:name[:qualifier]
+		if parts[1] == "function" && parts[2] != "" {
+			target := LambdaTarget{FunctionName: strings.Join(parts[:3], ":")}
+			if len(parts) == 4 {
+				target.Qualifier = parts[3]
+			}
+			return target, nil
+		}
+	}
+
+	return LambdaTarget{}, fmt.Errorf("invalid Lambda function %q (expected name, name:qualifier, account:function:name or a function ARN)", function)
+}
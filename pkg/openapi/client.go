@@ -12,7 +12,8 @@ import (
 
 // SetHeaders enriches an HTTP request with headers based on the OpenAPI specification.
 // It sets the Accept header based on response content types defined in the spec.
-// If no exact match is found or the spec is unavailable, it returns without error.
+// Concrete paths are matched against path templates, so /pet/123 resolves to /pet/{petId}.
+// If no operation matches or the spec is unavailable, it returns without error.
 func (v *Viewer) SetHeaders(ctx context.Context, req *http.Request, path, method string) error {
 	if v.specURL == "" {
 		// No spec URL, nothing to do
@@ -23,37 +24,28 @@ func (v *Viewer) SetHeaders(ctx context.Context, req *http.Request, path, method
 		return err
 	}
 
-	// Get all paths matching this pattern and method
-	paths, err := v.parser.GetPaths(path, method)
+	// Resolve the concrete path (e.g. /pet/123) to its templated operation
+	match, err := v.parser.MatchPath(path, method)
 	if err != nil {
 		// If no document loaded, just return without error
 		if err.Error() == "no OpenAPI document loaded" {
 			return nil
 		}
-		return fmt.Errorf("getting paths: %w", err)
+		return fmt.Errorf("matching path: %w", err)
 	}
 
-	// Find exact match
-	var exactMatch *PathInfo
-	for _, p := range paths {
-		if p.Path == path && strings.EqualFold(p.Method, method) {
-			exactMatch = &p
-			break
-		}
-	}
-
-	if exactMatch == nil {
-		// No exact match found, return without setting headers
+	if match == nil {
+		// No matching operation found, return without setting headers
 		return nil
 	}
 
 	// Extract and set Accept header from response content types
-	if exactMatch.Responses != nil && exactMatch.Responses.Codes != nil {
+	if match.Responses != nil && match.Responses.Codes != nil {
 		acceptTypes := []string{}
 		seenTypes := make(map[string]bool)
 
 		// Check successful response codes first (2xx)
-		for code, response := range exactMatch.Responses.Codes.FromOldest() {
+		for code, response := range match.Responses.Codes.FromOldest() {
 			if strings.HasPrefix(code, "2") && response.Content != nil {
 				for contentType := range response.Content.FromOldest() {
 					if !seenTypes[contentType] {
@@ -66,7 +58,7 @@ func (v *Viewer) SetHeaders(ctx context.Context, req *http.Request, path, method
 
 		// If no 2xx responses, check all responses
 		if len(acceptTypes) == 0 {
-			for _, response := range exactMatch.Responses.Codes.FromOldest() {
+			for _, response := range match.Responses.Codes.FromOldest() {
 				if response.Content != nil {
 					for contentType := range response.Content.FromOldest() {
 						if !seenTypes[contentType] {
@@ -85,9 +77,9 @@ func (v *Viewer) SetHeaders(ctx context.Context, req *http.Request, path, method
 	}
 
 	// Future: Set Content-Type header from request body when body support is added
-	// if exactMatch.RequestBody != nil && exactMatch.RequestBody.Content != nil && req.Body != nil {
+	// if match.RequestBody != nil && match.RequestBody.Content != nil && req.Body != nil {
 	//     // Extract first content type as default
-	//     for contentType := range exactMatch.RequestBody.Content.FromOldest() {
+	//     for contentType := range match.RequestBody.Content.FromOldest() {
 	//         req.Header.Set("Content-Type", contentType)
 	//         break
 	//     }
@@ -124,14 +116,23 @@ func (v *Viewer) BaseURL(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("getting servers from spec: %w", err)
 	}
 
+	if err := checkServerVariables(servers, v.serverVars); err != nil {
+		return "", err
+	}
+
 	// Priority 1: Use explicit server URL from OpenAPI spec if available
 	if len(servers) > 0 && servers[0].URL != "" {
-		serverURL := servers[0].URL
+		// Substitute {variable} placeholders with overrides or their defaults
+		serverURL, err := ExpandServerURL(servers[0], v.serverVars)
+		if err != nil {
+			return "", err
+		}
 
 		// Check if the server URL is absolute (has a supported scheme)
 		isAbsolute := strings.HasPrefix(serverURL, "http://") ||
 			strings.HasPrefix(serverURL, "https://") ||
-			strings.HasPrefix(serverURL, "lambda://")
+			strings.HasPrefix(serverURL, "lambda://") ||
+			strings.HasPrefix(serverURL, "lambda+")
 
 		if !isAbsolute {
 			// It's a relative URL - combine with OpenAPI URL host
@@ -3,6 +3,7 @@ package openapi
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // PathCompletions returns path completions for shell autocomplete
@@ -36,6 +37,17 @@ func (v *Viewer) ParamCompletions(ctx context.Context, path, method string) ([]s
 		return nil, fmt.Errorf("getting paths: %w", err)
 	}
 
+	// Fall back to template matching for concrete paths such as /pet/123
+	if len(paths) == 0 && !strings.Contains(path, "*") {
+		matches, err := v.parser.MatchPaths(path, method)
+		if err != nil {
+			return nil, fmt.Errorf("matching paths: %w", err)
+		}
+		for _, m := range matches {
+			paths = append(paths, m.PathInfo)
+		}
+	}
+
 	var paramNames []string
 	paramSet := make(map[string]bool)
 
@@ -58,19 +70,19 @@ func (v *Viewer) MethodCompletions(ctx context.Context, path string) ([]string,
 		return nil, err
 	}
 
-	paths, err := v.parser.GetPaths(path, "*")
+	// Resolve the path against templates so /pet/123 offers the methods of /pet/{petId}
+	matches, err := v.parser.MatchPaths(path, "*")
 	if err != nil {
-		return nil, fmt.Errorf("getting paths: %w", err)
+		return nil, fmt.Errorf("matching paths: %w", err)
 	}
 
 	var methods []string
 	methodSet := make(map[string]bool)
 
-	for _, pathInfo := range paths {
-		// Only include methods for exact path matches
-		if pathInfo.Path == path && !methodSet[pathInfo.Method] {
-			methods = append(methods, pathInfo.Method)
-			methodSet[pathInfo.Method] = true
+	for _, match := range matches {
+		if !methodSet[match.Method] {
+			methods = append(methods, match.Method)
+			methodSet[match.Method] = true
 		}
 	}
 
@@ -9,82 +9,97 @@ import (
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 )
 
+// The One Dark palette, shared with qurl's response formatter
+const (
+	ColorWhite          = lipgloss.Color("#FFFFFF")
+	ColorAccent         = lipgloss.Color("#5B47E0")
+	ColorBlue           = lipgloss.Color("#61AFEF")
+	ColorGreen          = lipgloss.Color("#98C379")
+	ColorYellow         = lipgloss.Color("#E5C07B")
+	ColorRed            = lipgloss.Color("#E06C75")
+	ColorPurple         = lipgloss.Color("#C678DD")
+	ColorCyan           = lipgloss.Color("#56B6C2")
+	ColorForeground     = lipgloss.Color("#ABB2BF")
+	ColorComment        = lipgloss.Color("#5C6370")
+	ColorCodeBackground = lipgloss.Color("#2C323C")
+)
+
 var (
 	titleStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#5B47E0")).
+			Foreground(ColorWhite).
+			Background(ColorAccent).
 			Padding(0, 2)
 
 	methodStyles = map[string]lipgloss.Style{
 		"GET": lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#61AFEF")).
+			Foreground(ColorWhite).
+			Background(ColorBlue).
 			Padding(0, 1),
 		"POST": lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#98C379")).
+			Foreground(ColorWhite).
+			Background(ColorGreen).
 			Padding(0, 1),
 		"PUT": lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#E5C07B")).
+			Foreground(ColorWhite).
+			Background(ColorYellow).
 			Padding(0, 1),
 		"DELETE": lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#E06C75")).
+			Foreground(ColorWhite).
+			Background(ColorRed).
 			Padding(0, 1),
 		"PATCH": lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#C678DD")).
+			Foreground(ColorWhite).
+			Background(ColorPurple).
 			Padding(0, 1),
 		"HEAD": lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#56B6C2")).
+			Foreground(ColorWhite).
+			Background(ColorCyan).
 			Padding(0, 1),
 		"OPTIONS": lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#ABB2BF")).
+			Foreground(ColorWhite).
+			Background(ColorForeground).
 			Padding(0, 1),
 	}
 
 	pathStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#E5C07B")).
+			Foreground(ColorYellow).
 			Bold(true)
 
 	summaryStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ABB2BF"))
+			Foreground(ColorForeground)
 
 	sectionStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#61AFEF")).
+			Foreground(ColorBlue).
 			MarginTop(1)
 
 	paramStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#98C379"))
+			Foreground(ColorGreen)
 
 	requiredStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#E06C75")).
+			Foreground(ColorRed).
 			Bold(true)
 
 	descriptionStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#ABB2BF")).
+				Foreground(ColorForeground).
 				MarginLeft(2)
 
 	codeStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#2C323C")).
-			Foreground(lipgloss.Color("#ABB2BF")).
+			Background(ColorCodeBackground).
+			Foreground(ColorForeground).
 			Padding(0, 1)
 
 	boxStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#5B47E0")).
+			BorderForeground(ColorAccent).
 			Padding(1).
 			MarginTop(1).
 			MarginBottom(1)
@@ -196,6 +211,13 @@ func (d *Displayer) RenderOperation(path PathInfo) string {
 		output.WriteString("\n")
 	}
 
+	if usage := d.renderUsage(path); usage != "" {
+		output.WriteString("\n")
+		output.WriteString(sectionStyle.Render("Usage"))
+		output.WriteString("\n\n")
+		output.WriteString(usage)
+	}
+
 	if len(path.Parameters) > 0 {
 		output.WriteString("\n")
 		output.WriteString(sectionStyle.Render("Parameters"))
@@ -220,6 +242,33 @@ func (d *Displayer) RenderOperation(path PathInfo) string {
 	return boxStyle.Render(output.String())
 }
 
+// renderUsage shows how to fill in path parameters with -p when the path is templated
+func (d *Displayer) renderUsage(path PathInfo) string {
+	var pathParams []string
+	for _, param := range path.Parameters {
+		if param.In != "path" || param.Name == "" {
+			continue
+		}
+		placeholder := param.Name
+		if param.Schema != nil && param.Schema.Schema() != nil && len(param.Schema.Schema().Type) > 0 {
+			placeholder = param.Schema.Schema().Type[0]
+		}
+		pathParams = append(pathParams, fmt.Sprintf("-p %s=<%s>", param.Name, placeholder))
+	}
+
+	if len(pathParams) == 0 {
+		return ""
+	}
+
+	command := "qurl"
+	if path.Method != "" && path.Method != "GET" {
+		command += " -X " + path.Method
+	}
+	command += " " + path.Path + " " + strings.Join(pathParams, " ")
+
+	return "  " + codeStyle.Render(command) + "\n"
+}
+
 func (d *Displayer) renderParameters(params []*v3.Parameter) string {
 	var output strings.Builder
 
@@ -377,19 +426,19 @@ func getStatusStyle(code string) lipgloss.Style {
 		switch code[0] {
 		case '2':
 			return lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#98C379")).
+				Foreground(ColorGreen).
 				Bold(true)
 		case '3':
 			return lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#61AFEF")).
+				Foreground(ColorBlue).
 				Bold(true)
 		case '4':
 			return lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#E5C07B")).
+				Foreground(ColorYellow).
 				Bold(true)
 		case '5':
 			return lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#E06C75")).
+				Foreground(ColorRed).
 				Bold(true)
 		}
 	}
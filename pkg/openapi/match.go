@@ -0,0 +1,232 @@
+package openapi
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PathMatch is an operation resolved from a concrete request path, together
+// with the path parameter values extracted from it.
+type PathMatch struct {
+	PathInfo
+	PathParams map[string]string
+}
+
+// templateParamPattern matches {name} placeholders in an OpenAPI path template
+var templateParamPattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// TemplateParams returns the names of the {name} placeholders in a path
+// template, in order of appearance and without repeats. Any query string or
+// fragment is ignored.
+func TemplateParams(template string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range templateParamPattern.FindAllStringSubmatch(stripQuery(template), -1) {
+		if !seen[match[1]] {
+			names = append(names, match[1])
+			seen[match[1]] = true
+		}
+	}
+	return names
+}
+
+// ExpandTemplate replaces each {name} placeholder in a path template with
+// value(name), leaving any query string or fragment untouched
+func ExpandTemplate(template string, value func(name string) string) string {
+	path := stripQuery(template)
+	expanded := templateParamPattern.ReplaceAllStringFunc(path, func(placeholder string) string {
+		return value(placeholder[1 : len(placeholder)-1])
+	})
+	return expanded + template[len(path):]
+}
+
+// MatchPathTemplate reports whether a concrete request path such as /pet/123
+// matches an OpenAPI path template such as /pet/{petId}. On a match it returns
+// the decoded path parameter values keyed by parameter name.
+func MatchPathTemplate(template, path string) (map[string]string, bool) {
+	templateSegments := splitPath(template)
+	pathSegments := splitPath(stripQuery(path))
+
+	if len(templateSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range templateSegments {
+		if !strings.Contains(segment, "{") {
+			if segment != pathSegments[i] {
+				return nil, false
+			}
+			continue
+		}
+
+		names := templateParamPattern.FindAllStringSubmatch(segment, -1)
+		if len(names) == 0 {
+			if segment != pathSegments[i] {
+				return nil, false
+			}
+			continue
+		}
+
+		values := segmentPattern(segment).FindStringSubmatch(pathSegments[i])
+		if values == nil {
+			return nil, false
+		}
+
+		for j, name := range names {
+			value := values[j+1]
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			params[name[1]] = value
+		}
+	}
+
+	return params, true
+}
+
+// MatchPaths resolves a concrete request path against the path templates in
+// the spec. An exact match always wins; otherwise, for each method, the most
+// specific matching template is chosen (literal segments beat parameters).
+func (p *Parser) MatchPaths(path, methodFilter string) ([]PathMatch, error) {
+	paths, err := p.GetPaths("*", methodFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	path = stripQuery(path)
+
+	type candidate struct {
+		match PathMatch
+		rank  []int
+	}
+	best := make(map[string]*candidate)
+
+	for _, info := range paths {
+		var params map[string]string
+		var rank []int
+
+		if info.Path == path {
+			// Exact matches outrank every templated match
+			params = map[string]string{}
+			rank = []int{1}
+		} else {
+			var ok bool
+			params, ok = MatchPathTemplate(info.Path, path)
+			if !ok {
+				continue
+			}
+			rank = append([]int{0}, templateRank(info.Path)...)
+		}
+
+		current, exists := best[info.Method]
+		if !exists || compareRank(rank, current.rank) > 0 {
+			best[info.Method] = &candidate{
+				match: PathMatch{PathInfo: info, PathParams: params},
+				rank:  rank,
+			}
+		}
+	}
+
+	var matches []PathMatch
+	for _, c := range best {
+		matches = append(matches, c.match)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Path != matches[j].Path {
+			return matches[i].Path < matches[j].Path
+		}
+		return methodOrder(matches[i].Method) < methodOrder(matches[j].Method)
+	})
+
+	return matches, nil
+}
+
+// MatchPath returns the operation that handles the given concrete path and
+// method, or nil if the spec does not define one.
+func (p *Parser) MatchPath(path, method string) (*PathMatch, error) {
+	matches, err := p.MatchPaths(path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, match := range matches {
+		if strings.EqualFold(match.Method, method) {
+			return &match, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// MatchPath loads the spec if needed and resolves the concrete path and method
+// to its OpenAPI operation. It returns nil if no operation matches.
+func (v *Viewer) MatchPath(ctx context.Context, path, method string) (*PathMatch, error) {
+	if err := v.ensureSpecLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	return v.parser.MatchPath(path, method)
+}
+
+// splitPath splits a path into segments, ignoring leading and trailing slashes
+func splitPath(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+// stripQuery removes any query string or fragment from a request path
+func stripQuery(path string) string {
+	if idx := strings.IndexAny(path, "?#"); idx >= 0 {
+		return path[:idx]
+	}
+	return path
+}
+
+// segmentPattern compiles a template segment such as "{name}.{ext}" into an
+// anchored regular expression with one capture group per placeholder
+func segmentPattern(segment string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	last := 0
+	for _, loc := range templateParamPattern.FindAllStringIndex(segment, -1) {
+		pattern.WriteString(regexp.QuoteMeta(segment[last:loc[0]]))
+		pattern.WriteString("(.+?)")
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(segment[last:]))
+	pattern.WriteString("$")
+
+	return regexp.MustCompile(pattern.String())
+}
+
+// templateRank scores each segment of a template: literal segments score 1,
+// templated segments 0, so earlier literal segments make a template more specific
+func templateRank(template string) []int {
+	segments := splitPath(template)
+	rank := make([]int, len(segments))
+	for i, segment := range segments {
+		if !strings.Contains(segment, "{") {
+			rank[i] = 1
+		}
+	}
+	return rank
+}
+
+// compareRank compares two ranks lexicographically
+func compareRank(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return len(a) - len(b)
+}
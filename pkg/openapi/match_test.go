@@ -0,0 +1,317 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const matchTestSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Match API", "version": "1.0.0"},
+	"paths": {
+		"/pet/{petId}": {
+			"get": {
+				"summary": "Find pet by ID",
+				"parameters": [
+					{"name": "petId", "in": "path", "required": true, "schema": {"type": "integer"}}
+				],
+				"responses": {
+					"200": {"description": "OK", "content": {"application/xml": {}}}
+				}
+			},
+			"delete": {
+				"responses": {"204": {"description": "Deleted"}}
+			}
+		},
+		"/pet/findByStatus": {
+			"get": {
+				"summary": "Find pets by status",
+				"responses": {
+					"200": {"description": "OK", "content": {"application/json": {}}}
+				}
+			}
+		},
+		"/users/{userId}/posts/{postId}": {
+			"get": {"responses": {"200": {"description": "OK"}}}
+		},
+		"/users/me/posts/{postId}": {
+			"get": {"responses": {"200": {"description": "OK"}}}
+		},
+		"/files/{name}.{ext}": {
+			"get": {"responses": {"200": {"description": "OK"}}}
+		}
+	}
+}`
+
+func TestTemplateParams(t *testing.T) {
+	tests := []struct {
+		template string
+		want     []string
+	}{
+		{"/pet/{petId}", []string{"petId"}},
+		{"/users/{userId}/posts/{postId}", []string{"userId", "postId"}},
+		{"/files/{name}.{ext}", []string{"name", "ext"}},
+		{"/a/{id}/b/{id}", []string{"id"}},
+		{"/search?q={query}", nil},
+		{"/pet/findByStatus", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got := TemplateParams(tt.template)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("TemplateParams(%q) = %v, want %v", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	values := map[string]string{"petId": "42", "q": "x"}
+	value := func(name string) string { return values[name] }
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"/pet/{petId}", "/pet/42"},
+		{"/pet/{petId}?tag={q}", "/pet/42?tag={q}"},
+		{"/pet/{petId}#{q}", "/pet/42#{q}"},
+		{"/pet", "/pet"},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandTemplate(tt.template, value); got != tt.want {
+			t.Errorf("ExpandTemplate(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestMatchPathTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		path       string
+		wantMatch  bool
+		wantParams map[string]string
+	}{
+		{
+			name:       "single parameter",
+			template:   "/pet/{petId}",
+			path:       "/pet/123",
+			wantMatch:  true,
+			wantParams: map[string]string{"petId": "123"},
+		},
+		{
+			name:       "multiple parameters",
+			template:   "/users/{userId}/posts/{postId}",
+			path:       "/users/42/posts/7",
+			wantMatch:  true,
+			wantParams: map[string]string{"userId": "42", "postId": "7"},
+		},
+		{
+			name:       "parameters within a segment",
+			template:   "/files/{name}.{ext}",
+			path:       "/files/report.pdf",
+			wantMatch:  true,
+			wantParams: map[string]string{"name": "report", "ext": "pdf"},
+		},
+		{
+			name:       "percent-encoded value is decoded",
+			template:   "/users/{name}",
+			path:       "/users/jane%20doe",
+			wantMatch:  true,
+			wantParams: map[string]string{"name": "jane doe"},
+		},
+		{
+			name:       "query string is ignored",
+			template:   "/pet/{petId}",
+			path:       "/pet/123?verbose=true",
+			wantMatch:  true,
+			wantParams: map[string]string{"petId": "123"},
+		},
+		{
+			name:       "trailing slash is ignored",
+			template:   "/pet/{petId}",
+			path:       "/pet/123/",
+			wantMatch:  true,
+			wantParams: map[string]string{"petId": "123"},
+		},
+		{
+			name:      "literal segment mismatch",
+			template:  "/pet/{petId}",
+			path:      "/store/123",
+			wantMatch: false,
+		},
+		{
+			name:      "segment count mismatch",
+			template:  "/pet/{petId}",
+			path:      "/pet/123/photos",
+			wantMatch: false,
+		},
+		{
+			name:      "empty parameter value",
+			template:  "/pet/{petId}/photos",
+			path:      "/pet//photos",
+			wantMatch: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, ok := MatchPathTemplate(tt.template, tt.path)
+			if ok != tt.wantMatch {
+				t.Fatalf("MatchPathTemplate(%q, %q) matched = %v, want %v", tt.template, tt.path, ok, tt.wantMatch)
+			}
+			if !ok {
+				return
+			}
+			if len(params) != len(tt.wantParams) {
+				t.Errorf("Expected %d params, got %d: %v", len(tt.wantParams), len(params), params)
+			}
+			for name, want := range tt.wantParams {
+				if params[name] != want {
+					t.Errorf("Param %q = %q, want %q", name, params[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParserMatchPath(t *testing.T) {
+	parser := NewParser()
+	if err := parser.LoadFromBytes([]byte(matchTestSpec)); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		method       string
+		wantTemplate string
+		wantParams   map[string]string
+	}{
+		{
+			name:         "concrete path resolves to template",
+			path:         "/pet/123",
+			method:       "GET",
+			wantTemplate: "/pet/{petId}",
+			wantParams:   map[string]string{"petId": "123"},
+		},
+		{
+			name:         "literal path beats template",
+			path:         "/pet/findByStatus",
+			method:       "GET",
+			wantTemplate: "/pet/findByStatus",
+			wantParams:   map[string]string{},
+		},
+		{
+			name:         "template used when literal lacks the method",
+			path:         "/pet/findByStatus",
+			method:       "DELETE",
+			wantTemplate: "/pet/{petId}",
+			wantParams:   map[string]string{"petId": "findByStatus"},
+		},
+		{
+			name:         "earlier literal segment is more specific",
+			path:         "/users/me/posts/9",
+			method:       "GET",
+			wantTemplate: "/users/me/posts/{postId}",
+			wantParams:   map[string]string{"postId": "9"},
+		},
+		{
+			name:         "exact template path matches itself",
+			path:         "/pet/{petId}",
+			method:       "get",
+			wantTemplate: "/pet/{petId}",
+			wantParams:   map[string]string{},
+		},
+		{
+			name:   "no match",
+			path:   "/store/inventory",
+			method: "GET",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := parser.MatchPath(tt.path, tt.method)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.wantTemplate == "" {
+				if match != nil {
+					t.Errorf("Expected no match, got %s %s", match.Method, match.Path)
+				}
+				return
+			}
+
+			if match == nil {
+				t.Fatalf("Expected match for %s %s", tt.method, tt.path)
+			}
+			if match.Path != tt.wantTemplate {
+				t.Errorf("Matched template = %q, want %q", match.Path, tt.wantTemplate)
+			}
+			if len(match.PathParams) != len(tt.wantParams) {
+				t.Errorf("Expected %d params, got %v", len(tt.wantParams), match.PathParams)
+			}
+			for name, want := range tt.wantParams {
+				if match.PathParams[name] != want {
+					t.Errorf("Param %q = %q, want %q", name, match.PathParams[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParserMatchPathNoDocument(t *testing.T) {
+	parser := NewParser()
+	if _, err := parser.MatchPath("/pet/1", "GET"); err == nil {
+		t.Error("Expected error when no document is loaded")
+	}
+}
+
+func TestTemplatedPathLookups(t *testing.T) {
+	viewer := NewViewer(&MockHTTPClient{}, "")
+	if err := viewer.parser.LoadFromBytes([]byte(matchTestSpec)); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	t.Run("docs render the templated operation", func(t *testing.T) {
+		output, err := viewer.renderPaths("/pet/123", "GET")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, expected := range []string{"/pet/{petId}", "Find pet by ID", "-p petId=<integer>"} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected output to contain %q", expected)
+			}
+		}
+	})
+
+	t.Run("method completions include templated methods", func(t *testing.T) {
+		methods, err := viewer.MethodCompletions(context.Background(), "/pet/123")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(methods, ",") != "GET,DELETE" {
+			t.Errorf("Expected GET,DELETE, got %v", methods)
+		}
+	})
+
+	t.Run("set headers uses the templated operation", func(t *testing.T) {
+		viewer.specURL = "file://unused"
+		defer func() { viewer.specURL = "" }()
+
+		req, _ := http.NewRequest("GET", "http://example.com/pet/123", nil)
+		if err := viewer.SetHeaders(context.Background(), req, "/pet/123", "GET"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if accept := req.Header.Get("Accept"); accept != "application/xml" {
+			t.Errorf("Expected Accept application/xml, got %q", accept)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package openapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
@@ -26,6 +28,7 @@ type Parser struct {
 	document   libopenapi.Document
 	model      *libopenapi.DocumentModel[v3.Document]
 	httpClient HTTPClient
+	timeout    time.Duration // Limit for fetching a spec by URL; 0 means no limit
 }
 
 func NewParser() *Parser {
@@ -40,6 +43,12 @@ func NewParserWithClient(client HTTPClient) *Parser {
 	}
 }
 
+// SetTimeout limits how long LoadFromURL may take to fetch a spec.
+// Zero, the default, leaves the limit to the caller's context.
+func (p *Parser) SetTimeout(timeout time.Duration) {
+	p.timeout = timeout
+}
+
 func (p *Parser) LoadFromURL(ctx context.Context, urlStr string) error {
 	// Parse URL to check scheme
 	parsedURL, err := url.Parse(urlStr)
@@ -69,6 +78,12 @@ func (p *Parser) LoadFromURL(ctx context.Context, urlStr string) error {
 	}
 
 	// Handle HTTP/HTTPS/Lambda URIs
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
 	if err != nil {
 		return fmt.Errorf("creating request: %w", err)
@@ -76,6 +91,9 @@ func (p *Parser) LoadFromURL(ctx context.Context, urlStr string) error {
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && p.timeout > 0 {
+			return fmt.Errorf("fetching OpenAPI spec: timed out after %s: %w", p.timeout, err)
+		}
 		return fmt.Errorf("fetching OpenAPI spec: %w", err)
 	}
 	defer resp.Body.Close()
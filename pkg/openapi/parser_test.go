@@ -2,6 +2,10 @@ package openapi
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -237,3 +241,28 @@ func indexOf(slice []string, item string) int {
 	}
 	return -1
 }
+
+func TestParserLoadFromURLTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	parser := NewParser()
+	parser.SetTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	err := parser.LoadFromURL(context.Background(), server.URL+"/openapi.json")
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("expected the timeout in the error, got %q", err.Error())
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("LoadFromURL took %s, expected it to stop after the timeout", elapsed)
+	}
+}
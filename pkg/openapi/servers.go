@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// ServerInfo is a server from the spec with its URL variables expanded
+type ServerInfo struct {
+	URL         string
+	Description string
+}
+
+// serverVariablePattern matches {name} placeholders in a server URL
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// ExpandServerURL substitutes {variable} placeholders in a server URL, using the
+// overrides first and the variable defaults from the spec otherwise. Values are
+// checked against the variable's enum when one is declared.
+func ExpandServerURL(server *v3.Server, overrides map[string]string) (string, error) {
+	if server == nil {
+		return "", fmt.Errorf("no server")
+	}
+
+	var expandErr error
+	expanded := serverVariablePattern.ReplaceAllStringFunc(server.URL, func(placeholder string) string {
+		if expandErr != nil {
+			return placeholder
+		}
+
+		name := placeholder[1 : len(placeholder)-1]
+
+		var variable *v3.ServerVariable
+		if server.Variables != nil {
+			variable = server.Variables.GetOrZero(name)
+		}
+
+		value, overridden := overrides[name]
+		if !overridden {
+			if variable == nil {
+				expandErr = fmt.Errorf("server variable %q is not defined in the spec and has no value (use --server-var %s=value)", name, name)
+				return placeholder
+			}
+			value = variable.Default
+		}
+
+		if variable != nil && len(variable.Enum) > 0 && !containsString(variable.Enum, value) {
+			expandErr = fmt.Errorf("invalid value %q for server variable %q (allowed: %s)", value, name, strings.Join(variable.Enum, ", "))
+			return placeholder
+		}
+
+		return value
+	})
+
+	if expandErr != nil {
+		return "", expandErr
+	}
+
+	return expanded, nil
+}
+
+// SetServerVariables sets values for server URL variables, overriding the spec defaults
+func (v *Viewer) SetServerVariables(vars map[string]string) {
+	v.serverVars = vars
+}
+
+// ResolvedServers returns every server in the spec with its URL variables expanded
+func (v *Viewer) ResolvedServers(ctx context.Context) ([]ServerInfo, error) {
+	if err := v.ensureSpecLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	servers, err := v.parser.GetServers()
+	if err != nil {
+		return nil, fmt.Errorf("getting servers from spec: %w", err)
+	}
+
+	if err := checkServerVariables(servers, v.serverVars); err != nil {
+		return nil, err
+	}
+
+	// Keep positions aligned with the spec so --server indices match the docs
+	resolved := make([]ServerInfo, len(servers))
+	for i, server := range servers {
+		if server == nil {
+			continue
+		}
+		serverURL, err := ExpandServerURL(server, v.serverVars)
+		if err != nil {
+			return nil, fmt.Errorf("server %d: %w", i, err)
+		}
+		resolved[i] = ServerInfo{URL: serverURL, Description: server.Description}
+	}
+
+	return resolved, nil
+}
+
+// checkServerVariables rejects overrides that no server declares (usually a typo)
+func checkServerVariables(servers []*v3.Server, overrides map[string]string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	declared := make(map[string]bool)
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+		if server.Variables != nil {
+			for name := range server.Variables.FromOldest() {
+				declared[name] = true
+			}
+		}
+		for _, match := range serverVariablePattern.FindAllStringSubmatch(server.URL, -1) {
+			declared[match[1]] = true
+		}
+	}
+
+	var unknown []string
+	for name := range overrides {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+
+	available := make([]string, 0, len(declared))
+	for name := range declared {
+		available = append(available, name)
+	}
+	sort.Strings(available)
+
+	if len(available) == 0 {
+		return fmt.Errorf("unknown server variables: %s (the spec declares none)", strings.Join(unknown, ", "))
+	}
+	return fmt.Errorf("unknown server variables: %s (available: %s)", strings.Join(unknown, ", "), strings.Join(available, ", "))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
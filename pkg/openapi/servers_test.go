@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const serverVariablesSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "API", "version": "1.0.0"},
+	"servers": [
+		{
+			"url": "https://{region}.api.example.com/{basePath}",
+			"variables": {
+				"region": {"default": "us", "enum": ["us", "eu"]},
+				"basePath": {"default": "v2"}
+			}
+		},
+		{"url": "https://{region}.staging.example.com", "variables": {"region": {"default": "us"}}}
+	],
+	"paths": {}
+}`
+
+func newServerVariablesViewer(vars map[string]string) *Viewer {
+	viewer := NewViewer(&MockHTTPClient{
+		Response: &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte(serverVariablesSpec))),
+		},
+	}, "https://api.example.com/openapi.json")
+	viewer.SetServerVariables(vars)
+	return viewer
+}
+
+func TestServerVariables(t *testing.T) {
+	tests := []struct {
+		name        string
+		vars        map[string]string
+		expected    string
+		expectError string
+	}{
+		{
+			name:     "defaults are substituted",
+			expected: "https://us.api.example.com/v2",
+		},
+		{
+			name:     "overrides replace defaults",
+			vars:     map[string]string{"region": "eu", "basePath": "beta"},
+			expected: "https://eu.api.example.com/beta",
+		},
+		{
+			name:        "value outside enum",
+			vars:        map[string]string{"region": "ap"},
+			expectError: `invalid value "ap" for server variable "region" (allowed: us, eu)`,
+		},
+		{
+			name:        "unknown variable",
+			vars:        map[string]string{"regoin": "eu"},
+			expectError: "unknown server variables: regoin (available: basePath, region)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := newServerVariablesViewer(tt.vars).BaseURL(context.Background())
+
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected BaseURL %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestResolvedServers(t *testing.T) {
+	servers, err := newServerVariablesViewer(map[string]string{"region": "eu"}).ResolvedServers(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var urls []string
+	for _, server := range servers {
+		urls = append(urls, server.URL)
+	}
+
+	expected := "https://eu.api.example.com/v2,https://eu.staging.example.com"
+	if strings.Join(urls, ",") != expected {
+		t.Errorf("Expected %s, got %v", expected, urls)
+	}
+}
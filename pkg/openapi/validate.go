@@ -0,0 +1,857 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// Violation describes a single way a request or response deviates from the spec.
+// Pointer is a JSON pointer into the message, e.g. /query/limit or /body/tags/0.
+type Violation struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// String formats the violation as "pointer: message"
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Pointer, v.Message)
+}
+
+// ValidateRequest checks the parts of an outgoing request against the operation:
+// path, query, header and cookie parameters (presence, type and enum) and the
+// request body against the requestBody schema for its content type.
+func (m *PathMatch) ValidateRequest(query url.Values, header http.Header, body []byte) []Violation {
+	v := &schemaValidator{request: true}
+	var violations []Violation
+
+	cookies := make(map[string]string)
+	for _, cookie := range (&http.Request{Header: header}).Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+
+	for _, param := range m.Parameters {
+		if param == nil || param.Name == "" {
+			continue
+		}
+
+		var values []string
+		switch param.In {
+		case "path":
+			if value, ok := m.PathParams[param.Name]; ok {
+				values = []string{value}
+			} else if strings.Contains(m.Path, "{"+param.Name+"}") && len(m.PathParams) == 0 {
+				// The template itself was requested, so there is no concrete value to check
+				continue
+			}
+		case "query":
+			values = query[param.Name]
+		case "header":
+			// Accept, Content-Type and Authorization are described elsewhere in OpenAPI
+			if isReservedHeader(param.Name) {
+				continue
+			}
+			values = header.Values(param.Name)
+		case "cookie":
+			if value, ok := cookies[param.Name]; ok {
+				values = []string{value}
+			}
+		default:
+			continue
+		}
+
+		pointer := "/" + param.In + "/" + escapePointer(param.Name)
+
+		if len(values) == 0 {
+			if param.In == "path" || (param.Required != nil && *param.Required) {
+				violations = append(violations, Violation{Pointer: pointer, Message: "required parameter is missing"})
+			}
+			continue
+		}
+
+		schema := proxySchema(param.Schema)
+		if schema == nil {
+			continue
+		}
+
+		violations = append(violations, v.validateParameter(schema, values, pointer)...)
+	}
+
+	violations = append(violations, v.validateBody(m.RequestBody, header.Get("Content-Type"), body)...)
+
+	return violations
+}
+
+// ValidateBody checks only the request body against the requestBody schema
+// for its content type, leaving parameters unchecked
+func (m *PathMatch) ValidateBody(contentType string, body []byte) []Violation {
+	v := &schemaValidator{request: true}
+	return v.validateBody(m.RequestBody, contentType, body)
+}
+
+// ValidateResponse checks a response against the operation: the status code must be
+// declared (exactly, as a range such as 2XX, or via default), the Content-Type must be
+// one of the declared media types and the body must match that media type's schema.
+func (m *PathMatch) ValidateResponse(statusCode int, header http.Header, body []byte) []Violation {
+	response := m.findResponse(statusCode)
+	if response == nil {
+		return []Violation{{
+			Pointer: "/status",
+			Message: fmt.Sprintf("status %d is not declared (expected %s)", statusCode, strings.Join(m.responseCodes(), ", ")),
+		}}
+	}
+
+	if len(body) == 0 || response.Content == nil || response.Content.Len() == 0 {
+		return nil
+	}
+
+	contentType := header.Get("Content-Type")
+	mediaTypeName, mediaType := findMediaType(response.Content, contentType)
+	if mediaType == nil {
+		return []Violation{{
+			Pointer: "/header/Content-Type",
+			Message: fmt.Sprintf("content type %q is not declared (expected %s)", contentType, strings.Join(mediaTypeNames(response.Content), ", ")),
+		}}
+	}
+
+	schema := proxySchema(mediaType.Schema)
+	if schema == nil {
+		return nil
+	}
+
+	v := &schemaValidator{request: false}
+	return v.validateEncodedBody(schema, mediaTypeName, body)
+}
+
+// findResponse returns the declared response for a status code, preferring an exact
+// code over a range (2XX) over the default response
+func (m *PathMatch) findResponse(statusCode int) *v3.Response {
+	if m.Responses == nil {
+		return nil
+	}
+
+	code := strconv.Itoa(statusCode)
+	if m.Responses.Codes != nil {
+		if response, ok := m.Responses.Codes.Get(code); ok {
+			return response
+		}
+		for declared, response := range m.Responses.Codes.FromOldest() {
+			if strings.EqualFold(declared, code[:1]+"XX") {
+				return response
+			}
+		}
+	}
+
+	return m.Responses.Default
+}
+
+// responseCodes lists the declared response codes in order, including default
+func (m *PathMatch) responseCodes() []string {
+	var codes []string
+	if m.Responses == nil {
+		return codes
+	}
+	if m.Responses.Codes != nil {
+		for code := range m.Responses.Codes.FromOldest() {
+			codes = append(codes, code)
+		}
+	}
+	if m.Responses.Default != nil {
+		codes = append(codes, "default")
+	}
+	return codes
+}
+
+// validateBody checks a request body against the operation's requestBody
+func (v *schemaValidator) validateBody(requestBody *v3.RequestBody, contentType string, body []byte) []Violation {
+	if requestBody == nil {
+		return nil
+	}
+
+	if len(body) == 0 {
+		if requestBody.Required != nil && *requestBody.Required {
+			return []Violation{{Pointer: "/body", Message: "request body is required"}}
+		}
+		return nil
+	}
+
+	if requestBody.Content == nil || requestBody.Content.Len() == 0 {
+		return nil
+	}
+
+	mediaTypeName, mediaType := findMediaType(requestBody.Content, contentType)
+	if mediaType == nil {
+		return []Violation{{
+			Pointer: "/header/Content-Type",
+			Message: fmt.Sprintf("content type %q is not accepted (expected %s)", contentType, strings.Join(mediaTypeNames(requestBody.Content), ", ")),
+		}}
+	}
+
+	schema := proxySchema(mediaType.Schema)
+	if schema == nil {
+		return nil
+	}
+
+	if actual, params, err := mime.ParseMediaType(contentType); err == nil && actual == "multipart/form-data" {
+		return v.validateMultipart(schema, mediaType, params["boundary"], body)
+	}
+
+	return v.validateEncodedBody(schema, mediaTypeName, body)
+}
+
+// validateEncodedBody decodes a body according to its media type and validates it
+func (v *schemaValidator) validateEncodedBody(schema *base.Schema, mediaType string, body []byte) []Violation {
+	switch {
+	case isJSONMediaType(mediaType):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return []Violation{{Pointer: "/body", Message: fmt.Sprintf("invalid JSON: %v", err)}}
+		}
+		return v.validate(schema, value, "/body")
+	case mediaType == "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return []Violation{{Pointer: "/body", Message: fmt.Sprintf("invalid form body: %v", err)}}
+		}
+		return v.validateForm(schema, form, "/body")
+	}
+
+	// Other media types (binary, text, XML) are not schema-validated
+	return nil
+}
+
+// validateForm validates url-encoded form fields against an object schema,
+// coercing each field to its property type
+func (v *schemaValidator) validateForm(schema *base.Schema, form url.Values, pointer string) []Violation {
+	var violations []Violation
+
+	for _, name := range schema.Required {
+		if _, ok := form[name]; !ok && !v.skipRequired(schema, name) {
+			violations = append(violations, Violation{Pointer: pointer + "/" + escapePointer(name), Message: "required property is missing"})
+		}
+	}
+
+	names := make([]string, 0, len(form))
+	for name := range form {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propPointer := pointer + "/" + escapePointer(name)
+		if schema.Properties == nil {
+			continue
+		}
+		propSchema := proxySchema(schema.Properties.GetOrZero(name))
+		if propSchema == nil {
+			if additionalPropertiesForbidden(schema) {
+				violations = append(violations, Violation{Pointer: propPointer, Message: "property is not allowed"})
+			}
+			continue
+		}
+		if len(form[name]) == 0 {
+			// Present without a text value, e.g. a multipart file part
+			continue
+		}
+		violations = append(violations, v.validateParameter(propSchema, form[name], propPointer)...)
+	}
+
+	return violations
+}
+
+// validateMultipart validates multipart/form-data parts against an object schema.
+// Field names must be declared by the schema, text fields are validated like
+// url-encoded form fields, and file parts are checked against the content type
+// declared in the media type's encoding, if any.
+func (v *schemaValidator) validateMultipart(schema *base.Schema, mediaType *v3.MediaType, boundary string, body []byte) []Violation {
+	if boundary == "" {
+		return []Violation{{Pointer: "/header/Content-Type", Message: "multipart content type has no boundary"}}
+	}
+
+	form := make(url.Values)
+	var violations []Violation
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return []Violation{{Pointer: "/body", Message: fmt.Sprintf("invalid multipart body: %v", err)}}
+		}
+
+		name := part.FormName()
+		if name == "" {
+			part.Close()
+			continue
+		}
+		pointer := "/body/" + escapePointer(name)
+
+		if part.FileName() != "" {
+			// File contents are not schema-validated; record the field as present
+			if _, ok := form[name]; !ok {
+				form[name] = nil
+			}
+			violations = append(violations, checkPartContentType(mediaType, name, part.Header.Get("Content-Type"), pointer)...)
+			part.Close()
+			continue
+		}
+
+		value, err := io.ReadAll(part)
+		part.Close()
+		if err != nil {
+			return []Violation{{Pointer: pointer, Message: fmt.Sprintf("invalid multipart body: %v", err)}}
+		}
+		form[name] = append(form[name], string(value))
+	}
+
+	violations = append(violations, v.validateForm(schema, form, "/body")...)
+
+	// Unlike JSON objects, multipart fields are rejected when the schema does not
+	// declare them, since a misspelled field name is otherwise silently ignored
+	if schema.Properties != nil && schema.Properties.Len() > 0 && schema.AdditionalProperties == nil {
+		names := make([]string, 0, len(form))
+		for name := range form {
+			if _, ok := schema.Properties.Get(name); !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			violations = append(violations, Violation{
+				Pointer: "/body/" + escapePointer(name),
+				Message: fmt.Sprintf("form field is not defined (expected %s)", strings.Join(propertyNames(schema), ", ")),
+			})
+		}
+	}
+
+	return violations
+}
+
+// checkPartContentType checks a file part against encoding.<field>.contentType,
+// which may list several comma-separated media types or wildcards
+func checkPartContentType(mediaType *v3.MediaType, name, contentType, pointer string) []Violation {
+	if mediaType.Encoding == nil {
+		return nil
+	}
+	encoding := mediaType.Encoding.GetOrZero(name)
+	if encoding == nil || encoding.ContentType == "" {
+		return nil
+	}
+
+	actual, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		actual = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	for _, declared := range strings.Split(encoding.ContentType, ",") {
+		declared = strings.ToLower(strings.TrimSpace(declared))
+		if declared == actual || mediaTypeMatches(declared, actual) {
+			return nil
+		}
+	}
+
+	return []Violation{{
+		Pointer: pointer,
+		Message: fmt.Sprintf("content type %q is not accepted (expected %s)", contentType, encoding.ContentType),
+	}}
+}
+
+// propertyNames returns the declared property names of an object schema, in spec order
+func propertyNames(schema *base.Schema) []string {
+	var names []string
+	if schema.Properties != nil {
+		for name := range schema.Properties.FromOldest() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// validateParameter coerces raw string values to the schema's type and validates them
+func (v *schemaValidator) validateParameter(schema *base.Schema, values []string, pointer string) []Violation {
+	if hasType(schema, "array") {
+		// Support both repeated (?tag=a&tag=b) and comma-delimited (?tag=a,b) forms
+		var items []string
+		for _, value := range values {
+			items = append(items, strings.Split(value, ",")...)
+		}
+
+		itemSchema := itemsSchema(schema)
+		array := make([]interface{}, 0, len(items))
+		var violations []Violation
+		for i, item := range items {
+			coerced, err := coerceString(itemSchema, item)
+			if err != nil {
+				violations = append(violations, Violation{Pointer: fmt.Sprintf("%s/%d", pointer, i), Message: err.Error()})
+				continue
+			}
+			array = append(array, coerced)
+		}
+		if len(violations) > 0 {
+			return violations
+		}
+		return v.validate(schema, array, pointer)
+	}
+
+	coerced, err := coerceString(schema, values[0])
+	if err != nil {
+		return []Violation{{Pointer: pointer, Message: err.Error()}}
+	}
+	return v.validate(schema, coerced, pointer)
+}
+
+// schemaValidator validates decoded JSON values against OpenAPI schemas.
+// In request mode readOnly properties are not required; in response mode writeOnly ones are not.
+type schemaValidator struct {
+	request bool
+}
+
+// validate checks a decoded JSON value against a schema
+func (v *schemaValidator) validate(schema *base.Schema, value interface{}, pointer string) []Violation {
+	if schema == nil {
+		return nil
+	}
+
+	var violations []Violation
+
+	for _, proxy := range schema.AllOf {
+		violations = append(violations, v.validate(proxySchema(proxy), value, pointer)...)
+	}
+
+	if len(schema.AnyOf) > 0 && v.countMatches(schema.AnyOf, value, pointer) == 0 {
+		violations = append(violations, Violation{Pointer: pointer, Message: "value does not match any of the anyOf schemas"})
+	}
+
+	if len(schema.OneOf) > 0 {
+		if matches := v.countMatches(schema.OneOf, value, pointer); matches != 1 {
+			violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("value must match exactly one oneOf schema (matched %d)", matches)})
+		}
+	}
+
+	if value == nil {
+		if len(schema.Type) == 0 || hasType(schema, "null") || (schema.Nullable != nil && *schema.Nullable) {
+			return violations
+		}
+		return append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("expected %s, got null", strings.Join(schema.Type, " or "))})
+	}
+
+	if len(schema.Type) > 0 && !matchesAnyType(schema.Type, value) {
+		return append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("expected %s, got %s", strings.Join(schema.Type, " or "), jsonTypeOf(value))})
+	}
+
+	if len(schema.Enum) > 0 {
+		var allowed []interface{}
+		found := false
+		for _, node := range schema.Enum {
+			var enumValue interface{}
+			if node == nil || node.Decode(&enumValue) != nil {
+				continue
+			}
+			enumValue = normalizeValue(enumValue)
+			allowed = append(allowed, enumValue)
+			if reflect.DeepEqual(enumValue, value) {
+				found = true
+			}
+		}
+		if !found && len(allowed) > 0 {
+			violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("value %s is not one of %s", formatValue(value), formatValue(allowed))})
+		}
+	}
+
+	switch typed := value.(type) {
+	case string:
+		violations = append(violations, validateString(schema, typed, pointer)...)
+	case float64:
+		violations = append(violations, validateNumber(schema, typed, pointer)...)
+	case []interface{}:
+		violations = append(violations, v.validateArray(schema, typed, pointer)...)
+	case map[string]interface{}:
+		violations = append(violations, v.validateObject(schema, typed, pointer)...)
+	}
+
+	return violations
+}
+
+// countMatches returns how many of the schemas validate the value without violations
+func (v *schemaValidator) countMatches(proxies []*base.SchemaProxy, value interface{}, pointer string) int {
+	matches := 0
+	for _, proxy := range proxies {
+		if len(v.validate(proxySchema(proxy), value, pointer)) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func validateString(schema *base.Schema, value, pointer string) []Violation {
+	var violations []Violation
+	length := int64(utf8.RuneCountInString(value))
+
+	if schema.MinLength != nil && length < *schema.MinLength {
+		violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("length %d is shorter than minLength %d", length, *schema.MinLength)})
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("length %d is longer than maxLength %d", length, *schema.MaxLength)})
+	}
+	if schema.Pattern != "" {
+		if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(value) {
+			violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("value %q does not match pattern %q", value, schema.Pattern)})
+		}
+	}
+
+	return violations
+}
+
+func validateNumber(schema *base.Schema, value float64, pointer string) []Violation {
+	var violations []Violation
+
+	if schema.Minimum != nil {
+		exclusive := schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.IsA() && schema.ExclusiveMinimum.A
+		if value < *schema.Minimum || (exclusive && value == *schema.Minimum) {
+			violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("value %v is below the minimum %v", value, *schema.Minimum)})
+		}
+	}
+	if schema.ExclusiveMinimum != nil && schema.ExclusiveMinimum.IsB() && value <= schema.ExclusiveMinimum.B {
+		violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("value %v must be greater than %v", value, schema.ExclusiveMinimum.B)})
+	}
+	if schema.Maximum != nil {
+		exclusive := schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.IsA() && schema.ExclusiveMaximum.A
+		if value > *schema.Maximum || (exclusive && value == *schema.Maximum) {
+			violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("value %v is above the maximum %v", value, *schema.Maximum)})
+		}
+	}
+	if schema.ExclusiveMaximum != nil && schema.ExclusiveMaximum.IsB() && value >= schema.ExclusiveMaximum.B {
+		violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("value %v must be less than %v", value, schema.ExclusiveMaximum.B)})
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf != 0 {
+		if quotient := value / *schema.MultipleOf; quotient != math.Trunc(quotient) {
+			violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("value %v is not a multiple of %v", value, *schema.MultipleOf)})
+		}
+	}
+
+	return violations
+}
+
+func (v *schemaValidator) validateArray(schema *base.Schema, value []interface{}, pointer string) []Violation {
+	var violations []Violation
+	count := int64(len(value))
+
+	if schema.MinItems != nil && count < *schema.MinItems {
+		violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("array has %d items, fewer than minItems %d", count, *schema.MinItems)})
+	}
+	if schema.MaxItems != nil && count > *schema.MaxItems {
+		violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("array has %d items, more than maxItems %d", count, *schema.MaxItems)})
+	}
+	if schema.UniqueItems != nil && *schema.UniqueItems {
+		for i := 0; i < len(value); i++ {
+			for j := i + 1; j < len(value); j++ {
+				if reflect.DeepEqual(value[i], value[j]) {
+					violations = append(violations, Violation{Pointer: fmt.Sprintf("%s/%d", pointer, j), Message: fmt.Sprintf("duplicate of item %d", i)})
+				}
+			}
+		}
+	}
+
+	if itemSchema := itemsSchema(schema); itemSchema != nil {
+		for i, item := range value {
+			violations = append(violations, v.validate(itemSchema, item, fmt.Sprintf("%s/%d", pointer, i))...)
+		}
+	}
+
+	return violations
+}
+
+func (v *schemaValidator) validateObject(schema *base.Schema, value map[string]interface{}, pointer string) []Violation {
+	var violations []Violation
+
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok && !v.skipRequired(schema, name) {
+			violations = append(violations, Violation{Pointer: pointer + "/" + escapePointer(name), Message: "required property is missing"})
+		}
+	}
+
+	count := int64(len(value))
+	if schema.MinProperties != nil && count < *schema.MinProperties {
+		violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("object has %d properties, fewer than minProperties %d", count, *schema.MinProperties)})
+	}
+	if schema.MaxProperties != nil && count > *schema.MaxProperties {
+		violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("object has %d properties, more than maxProperties %d", count, *schema.MaxProperties)})
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propPointer := pointer + "/" + escapePointer(name)
+
+		if schema.Properties != nil {
+			if proxy, ok := schema.Properties.Get(name); ok {
+				violations = append(violations, v.validate(proxySchema(proxy), value[name], propPointer)...)
+				continue
+			}
+		}
+
+		if schema.AdditionalProperties != nil {
+			if schema.AdditionalProperties.IsA() {
+				violations = append(violations, v.validate(proxySchema(schema.AdditionalProperties.A), value[name], propPointer)...)
+			} else if !schema.AdditionalProperties.B {
+				violations = append(violations, Violation{Pointer: propPointer, Message: "property is not allowed"})
+			}
+		}
+	}
+
+	return violations
+}
+
+// skipRequired reports whether a required property can be absent in the current
+// direction: readOnly properties are never sent, writeOnly ones never returned
+func (v *schemaValidator) skipRequired(schema *base.Schema, name string) bool {
+	if schema.Properties == nil {
+		return false
+	}
+	prop := proxySchema(schema.Properties.GetOrZero(name))
+	if prop == nil {
+		return false
+	}
+	if v.request {
+		return prop.ReadOnly != nil && *prop.ReadOnly
+	}
+	return prop.WriteOnly != nil && *prop.WriteOnly
+}
+
+// proxySchema safely resolves a schema proxy
+func proxySchema(proxy *base.SchemaProxy) *base.Schema {
+	if proxy == nil {
+		return nil
+	}
+	return proxy.Schema()
+}
+
+// itemsSchema returns the items schema of an array schema, if any
+func itemsSchema(schema *base.Schema) *base.Schema {
+	if schema.Items != nil && schema.Items.IsA() {
+		return proxySchema(schema.Items.A)
+	}
+	return nil
+}
+
+// additionalPropertiesForbidden reports whether additionalProperties is explicitly false
+func additionalPropertiesForbidden(schema *base.Schema) bool {
+	return schema.AdditionalProperties != nil && schema.AdditionalProperties.IsB() && !schema.AdditionalProperties.B
+}
+
+func hasType(schema *base.Schema, typeName string) bool {
+	for _, t := range schema.Type {
+		if t == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyType(types []string, value interface{}) bool {
+	for _, t := range types {
+		if matchesType(t, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(typeName string, value interface{}) bool {
+	switch typeName {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+// jsonTypeOf names the JSON type of a decoded value
+func jsonTypeOf(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		if typed == math.Trunc(typed) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// coerceString converts a raw parameter string into the JSON value its schema describes
+func coerceString(schema *base.Schema, raw string) (interface{}, error) {
+	if schema == nil || len(schema.Type) == 0 || hasType(schema, "string") {
+		return raw, nil
+	}
+	if hasType(schema, "integer") {
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return float64(n), nil
+		}
+		if !hasType(schema, "number") && !hasType(schema, "boolean") {
+			return nil, fmt.Errorf("expected integer, got %q", raw)
+		}
+	}
+	if hasType(schema, "number") {
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f, nil
+		}
+		if !hasType(schema, "boolean") {
+			return nil, fmt.Errorf("expected number, got %q", raw)
+		}
+	}
+	if hasType(schema, "boolean") {
+		if b, err := strconv.ParseBool(raw); err == nil && (raw == "true" || raw == "false") {
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected boolean, got %q", raw)
+	}
+	return raw, nil
+}
+
+// normalizeValue converts YAML-decoded values to their encoding/json equivalents
+func normalizeValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case int:
+		return float64(typed)
+	case int64:
+		return float64(typed)
+	case uint64:
+		return float64(typed)
+	case float32:
+		return float64(typed)
+	case []interface{}:
+		for i := range typed {
+			typed[i] = normalizeValue(typed[i])
+		}
+		return typed
+	case map[string]interface{}:
+		for key := range typed {
+			typed[key] = normalizeValue(typed[key])
+		}
+		return typed
+	}
+	return value
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// escapePointer escapes a reference token for use in a JSON pointer (RFC 6901)
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func isReservedHeader(name string) bool {
+	switch strings.ToLower(name) {
+	case "accept", "content-type", "authorization":
+		return true
+	}
+	return false
+}
+
+// isJSONMediaType reports whether a media type carries JSON (application/json, application/problem+json, ...)
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "/json")
+}
+
+// findMediaType finds the declared media type matching a Content-Type value,
+// honouring wildcards such as application/* and */*
+func findMediaType(content *orderedmap.Map[string, *v3.MediaType], contentType string) (string, *v3.MediaType) {
+	actual := strings.ToLower(contentType)
+	if parsed, _, err := mime.ParseMediaType(contentType); err == nil {
+		actual = parsed
+	}
+
+	var wildcardName string
+	var wildcard *v3.MediaType
+	for name, mediaType := range content.FromOldest() {
+		declared := strings.ToLower(name)
+		if parsed, _, err := mime.ParseMediaType(name); err == nil {
+			declared = parsed
+		}
+
+		if declared == actual {
+			return declared, mediaType
+		}
+		if wildcard == nil && mediaTypeMatches(declared, actual) {
+			wildcardName, wildcard = actual, mediaType
+		}
+	}
+
+	return wildcardName, wildcard
+}
+
+// mediaTypeMatches matches a declared media type range such as image/* against an actual type
+func mediaTypeMatches(declared, actual string) bool {
+	if declared == "*/*" {
+		return true
+	}
+	if strings.HasSuffix(declared, "/*") {
+		return strings.HasPrefix(actual, strings.TrimSuffix(declared, "*"))
+	}
+	return false
+}
+
+// mediaTypeNames lists the declared media types in order
+func mediaTypeNames(content *orderedmap.Map[string, *v3.MediaType]) []string {
+	var names []string
+	for name := range content.FromOldest() {
+		names = append(names, name)
+	}
+	return names
+}
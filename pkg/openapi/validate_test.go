@@ -0,0 +1,389 @@
+package openapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const validateTestSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Validate API", "version": "1.0.0"},
+	"paths": {
+		"/pet/{petId}": {
+			"get": {
+				"parameters": [
+					{"name": "petId", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}},
+					{"name": "X-Request-ID", "in": "header", "required": true, "schema": {"type": "string"}}
+				],
+				"responses": {"200": {"description": "OK"}}
+			}
+		},
+		"/pet/findByStatus": {
+			"get": {
+				"parameters": [
+					{"name": "status", "in": "query", "required": true, "schema": {"type": "string", "enum": ["available", "pending", "sold"]}},
+					{"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100}},
+					{"name": "tags", "in": "query", "schema": {"type": "array", "items": {"type": "string"}, "maxItems": 2}}
+				],
+				"responses": {"200": {"description": "OK"}}
+			}
+		},
+		"/pet": {
+			"post": {
+				"requestBody": {
+					"required": true,
+					"content": {
+						"application/json": {
+							"schema": {
+								"type": "object",
+								"required": ["id", "name", "photoUrls"],
+								"properties": {
+									"id": {"type": "integer", "readOnly": true},
+									"name": {"type": "string", "minLength": 1},
+									"photoUrls": {"type": "array", "items": {"type": "string"}},
+									"status": {"type": "string", "enum": ["available", "sold"]},
+									"category": {
+										"type": "object",
+										"additionalProperties": false,
+										"properties": {"name": {"type": "string"}}
+									},
+									"nickname": {"type": "string", "nullable": true}
+								}
+							}
+						},
+						"application/x-www-form-urlencoded": {
+							"schema": {
+								"type": "object",
+								"required": ["name"],
+								"properties": {
+									"name": {"type": "string"},
+									"age": {"type": "integer"}
+								}
+							}
+						},
+						"multipart/form-data": {
+							"schema": {
+								"type": "object",
+								"required": ["name", "photo"],
+								"properties": {
+									"name": {"type": "string"},
+									"age": {"type": "integer"},
+									"photo": {"type": "string", "format": "binary"}
+								}
+							},
+							"encoding": {"photo": {"contentType": "image/png, image/jpeg"}}
+						}
+					}
+				},
+				"responses": {"200": {"description": "OK"}}
+			}
+		}
+	}
+}`
+
+func TestValidateRequest(t *testing.T) {
+	parser := NewParser()
+	if err := parser.LoadFromBytes([]byte(validateTestSpec)); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		headers  map[string]string
+		body     string
+		expected []string // pointers of expected violations, in order
+	}{
+		{
+			name:    "valid path parameter and header",
+			method:  "GET",
+			path:    "/pet/42",
+			headers: map[string]string{"X-Request-ID": "abc"},
+		},
+		{
+			name:     "path parameter type and missing header",
+			method:   "GET",
+			path:     "/pet/abc",
+			expected: []string{"/path/petId", "/header/X-Request-ID"},
+		},
+		{
+			name:     "path parameter below minimum",
+			method:   "GET",
+			path:     "/pet/0",
+			headers:  map[string]string{"X-Request-ID": "abc"},
+			expected: []string{"/path/petId"},
+		},
+		{
+			name:     "missing required query parameter",
+			method:   "GET",
+			path:     "/pet/findByStatus",
+			expected: []string{"/query/status"},
+		},
+		{
+			name:     "query enum and maximum",
+			method:   "GET",
+			path:     "/pet/findByStatus?status=lost&limit=500",
+			expected: []string{"/query/limit", "/query/status"},
+		},
+		{
+			name:   "valid array query parameter",
+			method: "GET",
+			path:   "/pet/findByStatus?status=sold&tags=a,b",
+		},
+		{
+			name:     "array query parameter too long",
+			method:   "GET",
+			path:     "/pet/findByStatus?status=sold&tags=a&tags=b&tags=c",
+			expected: []string{"/query/tags"},
+		},
+		{
+			name:    "valid JSON body without readOnly property",
+			method:  "POST",
+			path:    "/pet",
+			headers: map[string]string{"Content-Type": "application/json"},
+			body:    `{"name": "doggie", "photoUrls": [], "nickname": null}`,
+		},
+		{
+			name:     "missing required body",
+			method:   "POST",
+			path:     "/pet",
+			expected: []string{"/body"},
+		},
+		{
+			name:     "invalid JSON body",
+			method:   "POST",
+			path:     "/pet",
+			headers:  map[string]string{"Content-Type": "application/json"},
+			body:     `{"name": `,
+			expected: []string{"/body"},
+		},
+		{
+			name:     "body schema violations",
+			method:   "POST",
+			path:     "/pet",
+			headers:  map[string]string{"Content-Type": "application/json; charset=utf-8"},
+			body:     `{"name": "", "photoUrls": [1], "status": "lost", "category": {"name": "dogs", "extra": true}}`,
+			expected: []string{"/body/category/extra", "/body/name", "/body/photoUrls/0", "/body/status"},
+		},
+		{
+			name:     "unsupported content type",
+			method:   "POST",
+			path:     "/pet",
+			headers:  map[string]string{"Content-Type": "text/plain"},
+			body:     "doggie",
+			expected: []string{"/header/Content-Type"},
+		},
+		{
+			name:     "form body",
+			method:   "POST",
+			path:     "/pet",
+			headers:  map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			body:     "age=old",
+			expected: []string{"/body/name", "/body/age"},
+		},
+		{
+			name:    "multipart body",
+			method:  "POST",
+			path:    "/pet",
+			headers: map[string]string{"Content-Type": "multipart/form-data; boundary=XYZ"},
+			body: multipartBody(
+				"Content-Disposition: form-data; name=\"name\"\r\n\r\ndoggie",
+				"Content-Disposition: form-data; name=\"photo\"; filename=\"dog.png\"\r\nContent-Type: image/png\r\n\r\n\x89PNG",
+			),
+		},
+		{
+			name:    "multipart field violations",
+			method:  "POST",
+			path:    "/pet",
+			headers: map[string]string{"Content-Type": "multipart/form-data; boundary=XYZ"},
+			body: multipartBody(
+				"Content-Disposition: form-data; name=\"nmae\"\r\n\r\ndoggie",
+				"Content-Disposition: form-data; name=\"age\"\r\n\r\nold",
+				"Content-Disposition: form-data; name=\"photo\"; filename=\"dog.txt\"\r\nContent-Type: text/plain\r\n\r\nwoof",
+			),
+			expected: []string{"/body/photo", "/body/name", "/body/age", "/body/nmae"},
+		},
+		{
+			name:     "multipart without boundary",
+			method:   "POST",
+			path:     "/pet",
+			headers:  map[string]string{"Content-Type": "multipart/form-data"},
+			body:     "name=doggie",
+			expected: []string{"/header/Content-Type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := parser.MatchPath(tt.path, tt.method)
+			if err != nil || match == nil {
+				t.Fatalf("Expected %s %s to match an operation (err: %v)", tt.method, tt.path, err)
+			}
+
+			query := url.Values{}
+			if idx := strings.Index(tt.path, "?"); idx >= 0 {
+				query, _ = url.ParseQuery(tt.path[idx+1:])
+			}
+			header := make(http.Header)
+			for key, value := range tt.headers {
+				header.Set(key, value)
+			}
+
+			violations := match.ValidateRequest(query, header, []byte(tt.body))
+
+			var pointers []string
+			for _, violation := range violations {
+				pointers = append(pointers, violation.Pointer)
+			}
+			if strings.Join(pointers, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Violations = %v, want pointers %v", violations, tt.expected)
+			}
+		})
+	}
+}
+
+// multipartBody joins raw parts (headers, blank line, content) with the boundary XYZ
+func multipartBody(parts ...string) string {
+	var body strings.Builder
+	for _, part := range parts {
+		body.WriteString("--XYZ\r\n" + part + "\r\n")
+	}
+	body.WriteString("--XYZ--\r\n")
+	return body.String()
+}
+
+func TestViolationString(t *testing.T) {
+	violation := Violation{Pointer: "/query/limit", Message: "expected integer, got \"ten\""}
+	if got := violation.String(); got != `/query/limit: expected integer, got "ten"` {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+const responseTestSpec = `{
+	"openapi": "3.0.0",
+	"info": {"title": "Response API", "version": "1.0.0"},
+	"paths": {
+		"/pet/{petId}": {
+			"get": {
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {
+							"application/json": {
+								"schema": {
+									"type": "object",
+									"required": ["id", "name", "password"],
+									"properties": {
+										"id": {"type": "integer"},
+										"name": {"type": "string"},
+										"password": {"type": "string", "writeOnly": true}
+									}
+								}
+							}
+						}
+					},
+					"4XX": {"description": "Client error", "content": {"application/*": {}}}
+				}
+			},
+			"delete": {
+				"responses": {
+					"204": {"description": "Deleted"},
+					"default": {"description": "Error", "content": {"application/json": {"schema": {"type": "object", "required": ["message"]}}}}
+				}
+			}
+		}
+	}
+}`
+
+func TestValidateResponse(t *testing.T) {
+	parser := NewParser()
+	if err := parser.LoadFromBytes([]byte(responseTestSpec)); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		status      int
+		contentType string
+		body        string
+		expected    []string
+	}{
+		{
+			name:        "valid response without writeOnly property",
+			method:      "GET",
+			status:      200,
+			contentType: "application/json",
+			body:        `{"id": 1, "name": "doggie"}`,
+		},
+		{
+			name:        "body schema violation",
+			method:      "GET",
+			status:      200,
+			contentType: "application/json",
+			body:        `{"id": "one"}`,
+			expected:    []string{"/body/name", "/body/id"},
+		},
+		{
+			name:        "undeclared content type",
+			method:      "GET",
+			status:      200,
+			contentType: "text/html",
+			body:        "<html></html>",
+			expected:    []string{"/header/Content-Type"},
+		},
+		{
+			name:     "undeclared status code",
+			method:   "GET",
+			status:   500,
+			expected: []string{"/status"},
+		},
+		{
+			name:        "status range with media type wildcard",
+			method:      "GET",
+			status:      404,
+			contentType: "application/problem+json",
+			body:        `{"title": "Not Found"}`,
+		},
+		{
+			name:   "declared status without content",
+			method: "DELETE",
+			status: 204,
+		},
+		{
+			name:        "default response",
+			method:      "DELETE",
+			status:      503,
+			contentType: "application/json",
+			body:        `{}`,
+			expected:    []string{"/body/message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := parser.MatchPath("/pet/1", tt.method)
+			if err != nil || match == nil {
+				t.Fatalf("Expected %s /pet/1 to match an operation (err: %v)", tt.method, err)
+			}
+
+			header := make(http.Header)
+			if tt.contentType != "" {
+				header.Set("Content-Type", tt.contentType)
+			}
+
+			violations := match.ValidateResponse(tt.status, header, []byte(tt.body))
+
+			var pointers []string
+			for _, violation := range violations {
+				pointers = append(pointers, violation.Pointer)
+			}
+			if strings.Join(pointers, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Violations = %v, want pointers %v", violations, tt.expected)
+			}
+		})
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Viewer struct {
-	parser    *Parser
-	displayer *Displayer
-	specURL   string
+	parser     *Parser
+	displayer  *Displayer
+	specURL    string
+	serverVars map[string]string // Overrides for server URL variables
 }
 
 func NewViewer(client HTTPClient, specURL string) *Viewer {
@@ -21,6 +23,11 @@ func NewViewer(client HTTPClient, specURL string) *Viewer {
 	}
 }
 
+// SetSpecTimeout limits how long fetching the spec may take; zero means no limit
+func (v *Viewer) SetSpecTimeout(timeout time.Duration) {
+	v.parser.SetTimeout(timeout)
+}
+
 // ensureSpecLoaded loads the OpenAPI spec if it hasn't been loaded yet
 func (v *Viewer) ensureSpecLoaded(ctx context.Context) error {
 	if v.specURL != "" && v.parser.model == nil {
@@ -61,12 +68,11 @@ func (v *Viewer) renderPaths(path, method string) (string, error) {
 		return "", fmt.Errorf("getting paths: %w", err)
 	}
 
-	if len(paths) == 0 {
-		return "No endpoints found matching the specified path and method", nil
-	}
-
 	// If trailing slash or wildcard, show index
 	if showIndex || path == "" || path == "*" {
+		if len(paths) == 0 {
+			return "No endpoints found matching the specified path and method", nil
+		}
 		return v.displayer.RenderIndex(paths), nil
 	}
 
@@ -86,6 +92,26 @@ func (v *Viewer) renderPaths(path, method string) (string, error) {
 		return v.displayer.RenderIndex(exactMatches), nil
 	}
 
-	// No exact match, show all matching paths as index
+	// No exact match, resolve concrete paths such as /pet/123 against path templates
+	matches, err := v.parser.MatchPaths(path, method)
+	if err != nil {
+		return "", fmt.Errorf("matching paths: %w", err)
+	}
+
+	if len(matches) == 1 {
+		return v.displayer.RenderOperation(matches[0].PathInfo), nil
+	} else if len(matches) > 0 {
+		var matched []PathInfo
+		for _, m := range matches {
+			matched = append(matched, m.PathInfo)
+		}
+		return v.displayer.RenderIndex(matched), nil
+	}
+
+	if len(paths) == 0 {
+		return "No endpoints found matching the specified path and method", nil
+	}
+
+	// No exact or templated match, show all matching paths as index
 	return v.displayer.RenderIndex(paths), nil
 }